@@ -0,0 +1,126 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// adminHandler only lets the users listed as admins in the config through. It must be wrapped by authHandler.
+func adminHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value("user").(user)
+
+		if !isAdmin(u.UserID) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+func isAdmin(userID string) bool {
+	for _, admin := range env.Admins {
+		if strings.EqualFold(admin, userID) {
+			return true
+		}
+	}
+	return false
+}
+
+// adminPage lets an admin revoke the stored grant of any user
+func adminPage(w http.ResponseWriter, r *http.Request) {
+	html, err := Asset("assets/admin.html")
+	if err != nil {
+		redirectToError(w, r, err)
+		return
+	}
+
+	t, _ := template.New("admin").Parse(string(html))
+
+	users, err := env.DataStore.ListUsers(r.Context())
+	if err != nil {
+		redirectToError(w, r, err)
+		return
+	}
+	sort.Strings(users)
+
+	u := r.Context().Value("user").(user)
+	adminData := struct {
+		UserID    string
+		Users     []string
+		Revoked   string
+		Deleted   string
+		CSRFToken string
+	}{UserID: u.UserID, Users: users, Revoked: r.URL.Query().Get("revoked"), Deleted: r.URL.Query().Get("deleted"), CSRFToken: csrfToken(r)}
+
+	t.Execute(w, adminData)
+}
+
+// adminRevokeHandler revokes a user's grant and signs them out everywhere. Their round-trips are kept but cannot be resumed until they log in again.
+func adminRevokeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	u := r.Context().Value("user").(user)
+
+	userID := strings.TrimSpace(r.FormValue("userId"))
+	if userID == "" {
+		redirectToError(w, r, errors.New("A user id is required"))
+		return
+	}
+
+	fmt.Printf("%s is revoking the grant of %s\n", u.UserID, userID)
+	if err := revokeUserGrant(r.Context(), userID); err != nil {
+		var revokeErr *revokeError
+		if !errors.As(err, &revokeErr) {
+			redirectToError(w, r, err)
+		} else if revokeErr.Deleted {
+			redirectToError(w, r, fmt.Errorf("The token of %s was deleted but could not be revoked with the auth server: %v", userID, revokeErr.Err))
+		} else {
+			redirectToError(w, r, fmt.Errorf("The token of %s could not be deleted, so they may still be signed in: %v", userID, revokeErr.Err))
+		}
+		return
+	}
+
+	http.Redirect(w, r, "/admin?revoked="+url.QueryEscape(userID), http.StatusSeeOther)
+}
+
+// adminDeleteHandler revokes a user's grant and then forgets everything stored for them, including their round-trips
+func adminDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	u := r.Context().Value("user").(user)
+
+	userID := strings.TrimSpace(r.FormValue("userId"))
+	if userID == "" {
+		redirectToError(w, r, errors.New("A user id is required"))
+		return
+	}
+
+	fmt.Printf("%s is deleting %s\n", u.UserID, userID)
+	if err := revokeUserGrant(r.Context(), userID); err != nil {
+		fmt.Printf("Revoking the grant of %s: %v\n", userID, err)
+	}
+
+	if err := env.DataStore.DeleteUser(r.Context(), userID); err != nil {
+		redirectToError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, "/admin?deleted="+url.QueryEscape(userID), http.StatusSeeOther)
+}
@@ -0,0 +1,217 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"time"
+
+	"bluebeam/gosessionroundtripper/studio"
+)
+
+// adoptPage lets the user pick one of their Studio Sessions, including those created directly in Revu, and map each of its files to the project file it should be checked in to
+func adoptPage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	u := ctx.Value("user").(user)
+
+	client, err := getStudioClient(ctx)
+	if err != nil {
+		redirectToError(w, r, err)
+		return
+	}
+
+	tracked, err := trackedSessions(ctx, u.UserID)
+	if err != nil {
+		redirectToError(w, r, err)
+		return
+	}
+
+	adoptData := struct {
+		UserID       string
+		Sessions     []*studio.SessionResponse
+		Tracked      map[string]bool
+		Session      *studio.SessionResponse
+		Files        []*studio.SessionFile
+		Projects     []*studio.Project
+		ProjectID    string
+		ProjectFiles []*studio.ProjectFile
+		CSRFToken    string
+	}{UserID: u.UserID, Tracked: tracked, CSRFToken: csrfToken(r)}
+
+	sessionID := r.URL.Query().Get("session")
+	if sessionID == "" {
+		// Without a Session picked yet, list them all
+		adoptData.Sessions, err = client.Sessions.All(ctx)
+		if err != nil {
+			redirectToError(w, r, err)
+			return
+		}
+	} else {
+		if tracked[sessionID] {
+			redirectToError(w, r, errors.New("This session is already tracked by a round-trip; finish it from My round-trips"))
+			return
+		}
+
+		adoptData.Session, err = client.Sessions.Get(ctx, sessionID)
+		if err != nil {
+			redirectToError(w, r, err)
+			return
+		}
+
+		adoptData.Files, err = client.Sessions.AllFiles(ctx, sessionID)
+		if err != nil {
+			redirectToError(w, r, err)
+			return
+		}
+
+		it := client.Projects.Iterate(0)
+		for it.Next(ctx) {
+			adoptData.Projects = append(adoptData.Projects, it.Project())
+		}
+		if err := it.Err(); err != nil {
+			redirectToError(w, r, err)
+			return
+		}
+
+		// The project files are listed once a Project is picked so they can be mapped to the Session files
+		adoptData.ProjectID = r.URL.Query().Get("project")
+		if adoptData.ProjectID != "" {
+			adoptData.ProjectFiles, err = client.Projects.AllFiles(ctx, adoptData.ProjectID)
+			if err != nil {
+				redirectToError(w, r, err)
+				return
+			}
+		}
+	}
+
+	html, err := Asset("assets/adopt.html")
+	if err != nil {
+		redirectToError(w, r, err)
+		return
+	}
+
+	t, _ := template.New("adopt").Parse(string(html))
+
+	t.Execute(w, adoptData)
+}
+
+// adoptFinishPage records an adopted Session as a round-trip with its files already checked out and starts finishing it
+func adoptFinishPage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	u := ctx.Value("user").(user)
+
+	sessionID := r.FormValue("session")
+	projectID := r.FormValue("project")
+	if sessionID == "" || projectID == "" {
+		redirectToError(w, r, errors.New("Choose a session and the project its files belong to"))
+		return
+	}
+
+	tracked, err := trackedSessions(ctx, u.UserID)
+	if err != nil {
+		redirectToError(w, r, err)
+		return
+	}
+	if tracked[sessionID] {
+		redirectToError(w, r, errors.New("This session is already tracked by a round-trip"))
+		return
+	}
+
+	// The job outlives the request so it cannot use the request context
+	client, err := newStudioClient(env.OAuthConfig.TokenSource(context.Background(), u.UserID, u.Token))
+	if err != nil {
+		redirectToError(w, r, err)
+		return
+	}
+
+	session, err := client.Sessions.Get(ctx, sessionID)
+	if err != nil {
+		redirectToError(w, r, err)
+		return
+	}
+
+	sessionFiles, err := client.Sessions.AllFiles(ctx, sessionID)
+	if err != nil {
+		redirectToError(w, r, err)
+		return
+	}
+
+	rt, err := newRoundTrip(u.UserID, session.Name, projectID)
+	if err != nil {
+		redirectToError(w, r, err)
+		return
+	}
+	rt.SessionID = session.ID
+	if end, err := time.Parse(time.RFC3339, session.SessionEndDate); err == nil {
+		rt.SessionEndDate = end
+	}
+
+	mapped := map[int]string{}
+	for _, f := range sessionFiles {
+		value := r.FormValue(fmt.Sprintf("file%d", f.ID))
+		if value == "" {
+			redirectToError(w, r, fmt.Errorf("Choose the project file for %s", f.Name))
+			return
+		}
+		if value == "skip" {
+			continue
+		}
+
+		fileProjectID, err := strconv.Atoi(value)
+		if err != nil {
+			redirectToError(w, r, fmt.Errorf("Invalid project file for %s", f.Name))
+			return
+		}
+		if other, ok := mapped[fileProjectID]; ok {
+			redirectToError(w, r, fmt.Errorf("%s and %s cannot both be checked in to the same project file", other, f.Name))
+			return
+		}
+		mapped[fileProjectID] = f.Name
+
+		rt.Files = append(rt.Files, &RoundTripFile{Name: f.Name, FileProjectID: fileProjectID, FileSessionID: f.ID, Step: fileCheckedOut})
+	}
+
+	if len(rt.Files) == 0 {
+		redirectToError(w, r, errors.New("Map at least one session file to a project file"))
+		return
+	}
+
+	// The Session and its files already exist so the round-trip picks up as if the app had created it
+	rt.State = roundTripActive
+	rt.Step = stepCheckedOut
+	if err := saveRoundTrip(rt); err != nil {
+		redirectToError(w, r, err)
+		return
+	}
+
+	if err := startFinish(rt, client); err != nil {
+		redirectToError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, "/finish/status?id="+rt.ID, http.StatusSeeOther)
+}
+
+// trackedSessions returns the ids of the Sessions a user has round-trips for that are not complete, as those are finished from My round-trips rather than adopted again
+func trackedSessions(ctx context.Context, userID string) (map[string]bool, error) {
+	roundTrips, err := env.DataStore.ListUserRoundTrips(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tracked := map[string]bool{}
+	for _, rt := range roundTrips {
+		if rt.SessionID != "" && rt.State != roundTripComplete {
+			tracked[rt.SessionID] = true
+		}
+	}
+
+	return tracked, nil
+}
@@ -5,7 +5,113 @@ $(document).on('change', ':file', function() {
     input.trigger('fileselect', [numFiles, label]);
 });
 
+function loadFolders() {
+    var project = $('#selectProject').val(),
+        select = $('#selectFolder');
+
+    if (!project || !select.length) {
+        return;
+    }
+
+    $.getJSON('/folders', { project: project }, function(folders) {
+        // The folder used last time is picked when the page first loads, if it is in the project
+        var selected = String(select.data('default'));
+        select.data('default', '');
+
+        select.empty();
+        $.each(folders, function(i, folder) {
+            var indent = new Array(folder.depth + 1).join('\u00a0\u00a0\u00a0\u00a0');
+            select.append($('<option>').val(folder.id).text(indent + folder.name));
+        });
+        if (select.find('option[value="' + selected + '"]').length) {
+            select.val(selected);
+        }
+        loadFiles();
+    });
+}
+
+function loadFiles() {
+    var project = $('#selectProject').val(),
+        folder = $('#selectFolder').val(),
+        select = $('#selectFiles');
+
+    if (!project || !select.length) {
+        return;
+    }
+
+    $.getJSON('/files', { project: project, folder: folder }, function(files) {
+        select.empty();
+        $.each(files, function(i, file) {
+            // Files already in a Session cannot be checked out again
+            select.append($('<option>').val(file.id).text(file.name).prop('disabled', file.checkedOut));
+        });
+    });
+}
+
+function toggleSource() {
+    var existing = $('input[name=source]:checked').val() === 'existing';
+
+    $('#uploadSource').toggle(!existing);
+    $('#existingSource').toggle(existing);
+    $('input[name=sessionFile]').prop('required', !existing);
+    $('#selectFiles').prop('required', existing);
+}
+
+function renderFinish(progress) {
+    var files = $('#files'),
+        rollback = $('#rollback');
+
+    $('#step').text(progress.step);
+    $('#message').text(progress.message).toggle(!!progress.message);
+
+    rollback.empty();
+    $.each(progress.rollback || [], function(i, step) {
+        rollback.append($('<li class="list-group-item">')
+            .addClass(step.done ? 'list-group-item-success' : 'list-group-item-danger')
+            .text(step.description + (step.error ? ' failed: ' + step.error : '')));
+    });
+
+    files.empty();
+    $.each(progress.files, function(i, file) {
+        var row = $('<tr>').addClass(file.failed ? 'danger' : (file.shareLink ? 'success' : '')),
+            link = $('<td>');
+
+        if (file.shareLink) {
+            link.append($('<a target="_blank">').attr('href', file.shareLink).text(file.shareLink));
+        }
+
+        row.append($('<td>').text(file.name));
+        row.append($('<td>').text(file.failed ? 'Failed at ' + file.step + ': ' + file.error : file.step));
+        row.append(link);
+        files.append(row);
+    });
+}
+
+function followFinish(id) {
+    var source = new EventSource('/finish/events?id=' + encodeURIComponent(id));
+
+    source.addEventListener('progress', function(e) {
+        renderFinish(JSON.parse(e.data));
+    });
+
+    source.addEventListener('done', function(e) {
+        var progress = JSON.parse(e.data);
+
+        source.close();
+        renderFinish(progress);
+        $('#title').text('Complete');
+        $('#progress').hide();
+        $('#summary').toggle(!progress.message);
+    });
+}
+
 $(document).ready( function() {
+    $('#selectProject').on('change', loadFolders);
+    $('#selectFolder').on('change', loadFiles);
+    $('input[name=source]').on('change', toggleSource);
+    loadFolders();
+    toggleSource();
+
     $(':file').on('fileselect', function(event, numFiles, label) {
 		var input = $(this).parents('.input-group').find(':text'),
 		log = numFiles > 1 ? numFiles + ' files selected' : label;
@@ -0,0 +1,217 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// backupVersion is the version of the export format written by export. Import refuses files of a newer version.
+const backupVersion = 1
+
+// Types of the lines of an export
+const (
+	backupHeader       = "header"
+	backupToken        = "token"
+	backupLoginSession = "loginSession"
+	backupUserPrefs    = "userPrefs"
+	backupRoundTrip    = "roundTrip"
+)
+
+// backupRecord is one line of an export, which is a JSON Lines file. The first line is the header, every other line holds a single record.
+type backupRecord struct {
+	Type string `json:"type"`
+	// Version and Created are only set on the header
+	Version int        `json:"version,omitempty"`
+	Created *time.Time `json:"created,omitempty"`
+
+	UserID string        `json:"userId,omitempty"`
+	Token  *oauth2.Token `json:"token,omitempty"`
+	// SealedToken replaces Token when the export encrypts tokens. It is sealed with the token keys like a stored token.
+	SealedToken  json.RawMessage `json:"sealedToken,omitempty"`
+	LoginSession *LoginSession   `json:"loginSession,omitempty"`
+	UserPrefs    *UserPrefs      `json:"userPrefs,omitempty"`
+	RoundTrip    *RoundTrip      `json:"roundTrip,omitempty"`
+}
+
+// exportRecords calls fn with every record of store worth keeping: the tokens, login sessions, preferences and round-trips. Each kind is listed on its own, so nothing is lost for users without a token. Leases and expired login sessions are left out as they only matter to running instances.
+// Tokens are sealed with seal unless it is nil.
+func exportRecords(ctx context.Context, store DataStore, seal *tokenCipher, fn func(rec *backupRecord) error) error {
+	users, err := store.ListUsers(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range users {
+		token, err := store.GetToken(ctx, userID)
+		if errors.Is(err, ErrNotFound) {
+			// Deleted since the users were listed
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		rec := &backupRecord{Type: backupToken, UserID: userID, Token: token}
+		if seal != nil {
+			data, err := json.Marshal(token)
+			if err != nil {
+				return err
+			}
+			if rec.SealedToken, err = seal.Seal(userID, data); err != nil {
+				return err
+			}
+			rec.Token = nil
+		}
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+
+	sessions, err := store.ListLoginSessions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, ls := range sessions {
+		if !ls.Expires.IsZero() && ls.Expires.Before(time.Now()) {
+			continue
+		}
+		if err := fn(&backupRecord{Type: backupLoginSession, LoginSession: ls}); err != nil {
+			return err
+		}
+	}
+
+	prefs, err := store.ListUserPrefs(ctx)
+	if err != nil {
+		return err
+	}
+	prefUsers := make([]string, 0, len(prefs))
+	for userID := range prefs {
+		prefUsers = append(prefUsers, userID)
+	}
+	sort.Strings(prefUsers)
+	for _, userID := range prefUsers {
+		if err := fn(&backupRecord{Type: backupUserPrefs, UserID: userID, UserPrefs: prefs[userID]}); err != nil {
+			return err
+		}
+	}
+
+	roundTrips, err := store.ListRoundTrips(ctx)
+	if err != nil {
+		return err
+	}
+	for _, rt := range roundTrips {
+		if err := fn(&backupRecord{Type: backupRoundTrip, RoundTrip: rt}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importRecord writes a record of an export to store, replacing any record with the same id. Sealed tokens are opened with tokens.
+func importRecord(ctx context.Context, store DataStore, tokens *tokenCipher, rec *backupRecord) error {
+	switch rec.Type {
+	case backupToken:
+		token := rec.Token
+		if rec.SealedToken != nil {
+			data, err := tokens.Open(rec.UserID, rec.SealedToken)
+			if err != nil {
+				return fmt.Errorf("Token of %s: %v", rec.UserID, err)
+			}
+			token = &oauth2.Token{}
+			if err := json.Unmarshal(data, token); err != nil {
+				return err
+			}
+		}
+		if token == nil || rec.UserID == "" {
+			return errors.New("Token record without a user or token")
+		}
+		return store.StoreToken(ctx, rec.UserID, token)
+	case backupLoginSession:
+		if rec.LoginSession == nil {
+			return errors.New("Login session record without a session")
+		}
+		return store.StoreLoginSession(ctx, rec.LoginSession)
+	case backupUserPrefs:
+		if rec.UserPrefs == nil || rec.UserID == "" {
+			return errors.New("Preferences record without a user or preferences")
+		}
+		return store.SaveUserPrefs(ctx, rec.UserID, rec.UserPrefs)
+	case backupRoundTrip:
+		if rec.RoundTrip == nil {
+			return errors.New("Round-trip record without a round-trip")
+		}
+		return store.SaveRoundTrip(ctx, rec.RoundTrip)
+	}
+
+	return fmt.Errorf("Unknown record type: %s", rec.Type)
+}
+
+// writeExport writes every record of store to w, returning how many records were written
+func writeExport(ctx context.Context, w io.Writer, store DataStore, seal *tokenCipher) (int, error) {
+	encoder := json.NewEncoder(w)
+
+	now := time.Now().UTC()
+	if err := encoder.Encode(&backupRecord{Type: backupHeader, Version: backupVersion, Created: &now}); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err := exportRecords(ctx, store, seal, func(rec *backupRecord) error {
+		count++
+		return encoder.Encode(rec)
+	})
+
+	return count, err
+}
+
+// readImport writes the records of an export read from r to store, returning how many records were imported
+func readImport(ctx context.Context, r io.Reader, store DataStore, tokens *tokenCipher) (int, error) {
+	reader := bufio.NewReader(r)
+
+	count := 0
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if err == io.EOF && len(data) == 0 {
+			if line == 1 {
+				return 0, errors.New("The export is empty")
+			}
+			return count, nil
+		}
+		if err != nil && err != io.EOF {
+			return count, err
+		}
+
+		rec := &backupRecord{}
+		if err := json.Unmarshal(data, rec); err != nil {
+			return count, fmt.Errorf("Line %d: %v", line, err)
+		}
+
+		if line == 1 {
+			if rec.Type != backupHeader {
+				return 0, errors.New("The export does not start with a header")
+			}
+			if rec.Version < 1 || rec.Version > backupVersion {
+				return 0, fmt.Errorf("Unsupported export version %d", rec.Version)
+			}
+			continue
+		}
+
+		if err := importRecord(ctx, store, tokens, rec); err != nil {
+			return count, fmt.Errorf("Line %d: %v", line, err)
+		}
+		count++
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// TestExportRoundTrip exports a database holding the records of a user with a token and of one without, imports it into another backend and checks that every record made it across
+func TestExportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	tokens := testTokenCipher(t)
+
+	from := &BoltDBStore{Path: filepath.Join(t.TempDir(), "my.db")}
+	if err := from.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer from.Close()
+
+	now := time.Now().UTC().Round(time.Second)
+	token := &oauth2.Token{AccessToken: "access", RefreshToken: "refresh", TokenType: "bearer", Expiry: now.Add(time.Hour)}
+	if err := from.StoreToken(ctx, "with-token", token); err != nil {
+		t.Fatal(err)
+	}
+	// Logging out deletes the token but leaves the rest of the user's records
+	for _, userID := range []string{"with-token", "without-token"} {
+		if err := from.StoreLoginSession(ctx, &LoginSession{ID: userID + "-session", UserID: userID, Created: now, Expires: now.Add(time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+		if err := from.SaveUserPrefs(ctx, userID, &UserPrefs{ProjectID: "project", FolderID: 7, Updated: now}); err != nil {
+			t.Fatal(err)
+		}
+		if err := from.SaveRoundTrip(ctx, &RoundTrip{ID: userID + "-roundtrip", UserID: userID, State: roundTripComplete, Created: now}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expired := &LoginSession{ID: "expired-session", UserID: "without-token", Created: now.Add(-2 * time.Hour), Expires: now.Add(-time.Hour)}
+	if err := from.StoreLoginSession(ctx, expired); err != nil {
+		t.Fatal(err)
+	}
+
+	var export bytes.Buffer
+	exported, err := writeExport(ctx, &export, from, tokens)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A token, and a login session, preferences and a round-trip for each user. The expired login session is left out.
+	if exported != 7 {
+		t.Errorf("Exported %d records, want 7", exported)
+	}
+
+	to := &SQLStore{Driver: "sqlite3", Source: filepath.Join(t.TempDir(), "my.sqlite")}
+	if err := to.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer to.Close()
+
+	imported, err := readImport(ctx, bytes.NewReader(export.Bytes()), to, tokens)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported != exported {
+		t.Errorf("Imported %d records, want the %d exported", imported, exported)
+	}
+
+	var again bytes.Buffer
+	reexported, err := writeExport(ctx, &again, to, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reexported != exported {
+		t.Errorf("The import exports %d records, want %d", reexported, exported)
+	}
+
+	got, err := to.GetToken(ctx, "with-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sameJSON(got, token); err != nil {
+		t.Errorf("Token: %v", err)
+	}
+	for _, userID := range []string{"with-token", "without-token"} {
+		if _, err := to.GetLoginSession(ctx, userID+"-session"); err != nil {
+			t.Errorf("Login session of %s: %v", userID, err)
+		}
+		if prefs, err := to.GetUserPrefs(ctx, userID); err != nil || prefs.ProjectID != "project" {
+			t.Errorf("Preferences of %s were not imported: %+v %v", userID, prefs, err)
+		}
+		if _, err := to.GetRoundTrip(ctx, userID+"-roundtrip"); err != nil {
+			t.Errorf("Round-trip of %s: %v", userID, err)
+		}
+	}
+	if _, err := to.GetLoginSession(ctx, expired.ID); err == nil {
+		t.Error("The expired login session was imported")
+	}
+}
+
+// TestMigrateTarget checks that migrate refuses a target that is missing or is the database being migrated, however its path is spelled
+func TestMigrateTarget(t *testing.T) {
+	dir := t.TempDir()
+	sqlite := &config{Database: "sqlite", DatabaseURL: filepath.Join(dir, "my.sqlite")}
+
+	for _, test := range []struct {
+		name   string
+		source *config
+		args   []string
+	}{
+		{"no target url", sqlite, []string{"sqlite"}},
+		{"empty url", sqlite, []string{"sqlite", ""}},
+		{"too many words", sqlite, []string{"sqlite", "other.sqlite", "extra"}},
+		{"same file", sqlite, []string{"sqlite", filepath.Join(dir, ".", "my.sqlite")}},
+		{"default bolt", &config{}, []string{"bolt", "my.db"}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			// The target is checked before either database is used
+			if err := migrateCommand(context.Background(), test.source, nil, nil, test.args); err == nil {
+				t.Errorf("migrate %v was not refused", test.args)
+			}
+		})
+	}
+
+	if sameDatabase(sqlite, &config{Database: "postgres", DatabaseURL: sqlite.DatabaseURL}) {
+		t.Error("Databases of different backends were taken for the same one")
+	}
+}
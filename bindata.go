@@ -1,10 +1,13 @@
 // Code generated by go-bindata.
 // sources:
+// assets/admin.html
+// assets/adopt.html
 // assets/create.html
 // assets/error.html
 // assets/finish.html
 // assets/home.html
 // assets/login.html
+// assets/roundtrips.html
 // assets/script.js
 // assets/style.css
 // DO NOT EDIT!
@@ -74,7 +77,47 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _assetsCreateHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x55\x4d\x73\xdb\x36\x10\xbd\xe7\x57\xec\xe0\xd0\x1c\x5a\x92\xe3\xc8\x6d\x3c\xa9\xa0\x8e\x1b\xdb\x8d\xea\xd6\xf6\x88\x4e\xa6\x3e\x75\x40\x62\x45\xc2\xc2\x07\x0b\x2c\x24\xb3\x99\xfc\xf7\x0e\x29\xd9\xa6\x18\x35\x33\xc9\x4d\x8b\xdd\xf7\xb8\xfb\xde\x02\x9a\xd6\x64\xf4\xec\x05\x00\xc0\xb4\x46\x21\xb7\x3f\xfb\xd0\x20\x09\x28\x6b\xe1\x03\x12\x67\x91\x96\xc9\x09\x1b\xa7\x6b\xa2\x26\xc1\x7f\xa2\x5a\x73\xf6\x57\xf2\xfe\x34\x79\xeb\x4c\x23\x48\x15\x1a\x19\x94\xce\x12\x5a\xe2\x6c\x7e\xce\x51\x56\xf8\x19\xda\x0a\x83\x9c\xad\x15\x6e\x1a\xe7\x69\x00\xd8\x28\x49\x35\x97\xb8\x56\x25\x26\x7d\xf0\x03\x28\xab\x48\x09\x9d\x84\x52\x68\xe4\x47\x43\x32\x52\xa4\x71\x96\x63\x08\xca\x59\x58\xb8\x68\x25\x79\xd5\x34\xe8\x21\x81\x3f\x85\x5f\xc5\x06\xbe\x83\x0b\x65\x55\xa8\xa7\xd9\xb6\xfa\x19\xad\x95\x5d\x81\x47\xcd\x59\xa0\x56\x63\xa8\x11\x89\x41\xed\x71\xc9\x59\x37\x5f\x78\x93\x65\x46\x3c\x94\xd2\xa6\x85\x73\x14\xc8\x8b\xa6\x0b\x4a\x67\xb2\xa7\x83\x6c\x92\x4e\xd2\xd7\x59\x19\xc2\xf3\x59\x6a\x94\x4d\xcb\x10\x18\x28\x4b\x58\x79\x45\x2d\x67\xa1\x16\x93\x93\xe3\xe4\xd7\x0f\x77\x4a\xe5\xf3\x0b\xbc\x3c\x92\xbf\x99\xdf\x17\xa7\xab\xb6\x8c\xef\x4e\xdf\x2d\xaa\xc9\xab\x6b\xf3\xbe\xdc\x6c\x5e\x3b\x3b\x59\xdc\xc9\xea\xf8\x83\xf8\xfe\xc6\xe4\xb7\xe1\xdf\xec\xf2\xa7\x93\x75\x21\xcf\xef\xeb\xe3\xc8\xa0\xf4\x2e\x04\xe7\x55\xa5\x2c\x67\xc2\x3a\xdb\x1a\x17\xc3\x4e\x96\x69\xf6\x6c\xe6\xb4\x70\xb2\x85\x7e\x36\xce\x8c\xf0\x95\xb2\x6f\xe0\xd5\x8f\xcd\xc3\xcf\x43\x0d\xa5\x5a\x43\xa9\x45\x08\x9c\x35\xa2\xc2\xa4\xc3\xa3\x1f\x54\x6c\x57\xe4\x68\xf6\x99\x9e\xf5\xd1\x80\x26\x93\x6a\x3d\x08\x9b\x7d\xfc\x6d\x8d\x90\x53\x94\xca\xc1\xa3\x5b\xd3\x40\xde\xd9\x6a\xf6\xf1\x63\xba\x3b\xba\x12\x06\x3f\x7d\x9a\x66\xbb\x04\xd4\x22\x40\x81\x68\xa1\xf4\x28\x08\x25\x6c\x14\xd5\x30\x3f\x3b\x00\x9d\x9f\x0d\x80\x29\xdc\xb9\x08\x46\xb4\x60\xdd\x06\x2a\xd7\xb9\xe0\x60\x81\xeb\x08\xc2\x4a\x30\xdb\x39\x5a\x17\x3d\x2c\x95\xc6\x14\xde\x6a\x55\xae\xe0\xe5\x76\xae\xc7\x06\x5f\xc2\xa6\x46\xdb\x95\x81\xf0\x08\x1e\x85\x6c\xd3\xc1\xbc\xcd\xff\x69\x68\x51\x8f\xd5\x1b\xe7\x93\xce\x99\x51\x51\x5f\x18\x8c\xd0\xfa\xb1\x94\xf0\x81\x12\x13\x09\x25\x9b\xe5\xe7\x79\x3e\xbf\xbe\x82\x3f\xe6\x57\x97\xd3\xac\x2f\x3b\x00\x1f\x7c\x67\x83\x7a\xdc\xc6\x53\x99\x18\x2d\x79\xe8\x9d\x49\x0b\x1d\xb1\x40\x61\xfa\x05\xbf\x77\xca\xa6\xdd\x1b\xf1\xcb\xfc\x8c\xef\x0b\xcd\x80\x84\xaf\xba\xb7\xe1\xef\x42\x0b\xbb\x62\x07\x3c\x14\x07\xba\xdb\x5f\x91\x43\x5b\x33\x0a\x97\xce\x1b\x10\x25\x29\x67\x39\xcb\x96\xbd\x3d\x0c\x0c\x52\xed\x24\x67\x37\xd7\xf9\xed\x17\x84\xee\xc0\x49\xe5\x5d\x6c\x0e\x09\xad\x6c\x13\x09\xa8\x6d\x90\xb3\x5a\x49\x89\x96\xed\x5e\xa5\xb0\x1b\x54\x32\x58\x0b\x1d\x91\xb3\xd1\xf4\x5f\xc5\xd6\x78\x77\x8f\x25\xed\xb3\xdd\xec\x0e\xbf\x9a\xad\x5b\xd7\xfc\x50\x7f\x17\x83\xc4\x37\xb1\xde\x1c\xea\xf3\x62\x90\xf8\x22\xeb\x4e\xf2\x82\x2c\x14\x64\x93\xc6\x2b\x23\x7c\xcb\x76\x5f\x0b\xb1\x30\x8a\x9e\x88\xf7\xaf\xd9\xd8\xc1\xf1\x46\x74\x36\x3e\x3e\x6d\xdd\xad\x99\xbd\x98\x66\xfd\x5f\xd7\x7f\x01\x00\x00\xff\xff\xbe\x58\xad\x0f\xc1\x06\x00\x00")
+var _assetsAdminHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xdd\x56\x6d\x6f\xdb\x36\x10\xfe\xbe\x5f\x41\x10\x03\xb2\xa1\x95\x84\xd4\xe9\x1a\xa4\x92\x87\xac\x2f\x4b\x56\x60\x1b\x9c\xa4\x68\x3e\x52\xe2\x59\xe2\x22\x92\x1a\x49\xc9\x51\x0d\xff\xf7\x1d\x29\xd5\x56\x12\xa7\x6b\xfb\xb1\xfe\x60\x90\x47\xde\xdb\x73\xcf\x1d\x95\x56\x4e\xd6\xf3\x1f\x08\xfe\xd2\x0a\x18\x1f\x96\x61\x2b\xc1\x31\x52\x54\xcc\x58\x70\x19\x6d\xdd\x32\x3a\xa6\xf7\x8f\x2b\xe7\x9a\x08\xfe\x6d\x45\x97\xd1\x0f\xd1\xd5\x69\xf4\x4a\xcb\x86\x39\x91\xd7\x40\x49\xa1\x95\x03\x85\xba\xe7\x6f\x32\xe0\x25\x3c\xd0\x56\x4c\x42\x46\x3b\x01\xab\x46\x1b\x37\x51\x58\x09\xee\xaa\x8c\x43\x27\x0a\x88\xc2\xe6\x29\x11\x4a\x38\xc1\xea\xc8\x16\xac\x86\xec\x70\x6a\xcc\x09\x57\xc3\xfc\x02\xac\x15\x5a\x91\x85\x6e\x15\x77\x46\x34\x0d\x18\x12\x91\x53\x2e\x85\x4a\x93\xe1\xce\x4e\xa7\x16\xea\x86\x18\xa8\x33\x6a\x5d\x5f\x83\xad\x00\x30\x82\xca\xc0\x32\xa3\x3e\x2b\x7b\x92\x24\x92\xdd\x16\x5c\xc5\xb9\xd6\xce\x3a\xc3\x1a\xbf\x29\xb4\x4c\xb6\x82\x64\x16\xcf\xe2\x17\x49\x61\xed\x4e\x16\xa3\xbb\x18\x25\x14\x23\x76\x50\x1a\xe1\x7a\xf4\x51\xb1\xd9\xf1\x51\xf4\xdb\xfb\x6b\x21\x2e\xce\xdf\xc2\xbb\x43\xfe\xbb\xfc\x63\x71\x7a\xd3\x17\xed\xd9\xe9\xd9\xa2\x9c\x3d\xfb\x4b\x5e\x15\xab\xd5\x0b\xad\x66\x8b\x6b\x5e\x1e\xbd\x67\x4f\xfe\x96\x17\x97\xf6\x63\xf2\xee\x97\xe3\x2e\xe7\x6f\xfe\xa9\x8e\x5a\x84\xc8\x68\x6b\xb5\x11\xa5\x50\x19\x65\x4a\xab\x5e\xea\xd6\x8e\x60\xa4\xc9\xae\x84\x69\xae\x79\x4f\x42\x6e\x19\x95\xcc\xa0\xc2\x09\x79\xf6\xbc\xb9\x7d\x39\x45\x8e\x8b\x8e\x14\x35\xb3\x36\xa3\x0d\x2b\x21\xf2\xfa\x60\x26\x37\x06\x62\x1c\xce\x47\x14\x71\xb5\x53\x4e\x50\x7b\xb2\x6d\x76\xeb\x6b\xdd\x12\x66\x80\xb0\xd6\x55\x18\xec\x47\xe0\x84\x59\xb2\x5e\xc7\x57\x16\xcc\xf9\xeb\xcd\x26\x26\x29\x1b\xc1\x4e\xe8\xfc\x4c\x4b\x48\x13\x36\x35\x3d\x31\xb6\x5e\x8b\x25\x89\x17\xd0\xe9\x1b\xe0\x9b\xcd\xde\xe0\x91\x12\xc6\x91\xf0\x1f\xd9\xb6\x28\x90\x0a\x74\x7e\x59\x01\x29\x0d\x53\x8e\xe8\xa5\x77\xbe\x35\x41\x2a\x8c\x26\x07\x50\x48\x80\x20\x8a\xef\xe5\xb2\x5e\x83\x9a\xba\x1a\x42\x78\x0d\x35\xb8\xaf\x0b\x01\xbd\x6e\xb5\x76\x5e\xf9\x20\xfa\x3f\xaf\x69\x35\x9b\x7b\xc0\x2c\xe2\x3e\xdb\x0f\xf4\x90\xd2\x98\x86\x25\x0e\x33\x6e\x51\xe3\x00\x97\x28\x50\x64\x25\x5c\x15\xa4\xbe\x12\x04\x4f\x3a\x30\x4f\x47\xff\x96\x08\x47\x96\x46\xcb\x70\x81\x33\xc7\x72\x66\xf1\xa6\xe2\xc4\x8a\x52\x05\x6b\x92\xe8\x36\xc0\x07\xa8\xd8\x93\xdc\xe8\x15\x1a\x89\x09\x42\xdb\x63\x3e\x1d\xa0\x9f\x5d\x95\x07\x4f\x4d\x43\x58\xc9\x84\xc2\x5c\x97\xda\x04\x61\x4f\x0a\xa6\x7c\x64\xde\xa5\x36\x5e\x24\x0c\x31\xbe\x51\x23\xdf\xa9\x36\x9c\xe7\x3e\x11\xdb\x4a\x44\x66\x9b\xe0\x80\x1e\xe1\x7a\xcc\xce\xe2\xc8\x08\x21\xb2\xda\xea\x6d\x22\x83\xbd\x06\xf9\x04\x06\x14\x42\x1f\xae\x4c\xec\xc7\xfb\xa9\x95\x62\xce\x35\x7c\xaa\x60\xd8\xdc\xe7\xbe\xf3\x7d\x74\x57\x36\xd4\x0a\x89\x55\x02\x09\x8c\xb6\x93\xa2\xed\x14\xcd\x43\xad\xe1\x80\x7b\x5a\x6c\x36\x38\x93\xf8\xe3\x57\xf6\x1e\x84\x43\x04\x55\x12\x56\x38\x9c\x75\xd8\x3d\xcc\x37\x66\x32\x10\x80\x12\x1c\xab\x95\xe6\xd8\xcc\xda\xe2\x34\x1b\xbb\x9f\x0b\xdb\xd4\xac\x3f\xc1\x81\x84\x53\x0f\x5e\xd2\xc7\x8d\x07\x07\x42\x35\x58\x74\xd7\x37\xa8\x5b\x09\xce\x41\xd1\x71\x54\x17\xd6\x2c\x2f\x3d\xb1\x28\xe9\x58\xdd\xa2\x64\xbd\xfe\x31\x7e\x75\xb1\x78\x1b\xa4\x9b\xcd\xb7\x9b\xf6\xb4\x3d\xe7\x13\xbb\xf1\x97\x5a\x1b\xab\x97\x3b\x64\x90\x53\x11\x87\x25\x6b\x6b\x17\xd6\x56\xd2\xd1\x99\x6d\x73\x29\xdc\xd6\xfc\xd0\x37\x9f\xb1\x9f\x26\x1e\xe6\xaf\xac\xc2\x40\xc7\x2f\xad\x02\xd1\x6a\x88\x2a\xa3\x06\x5c\x6b\x94\x7f\xff\x96\xc2\xc8\x9f\x0e\x46\xd6\x07\x10\x46\xb6\xd7\xbe\x0b\x1f\xf4\xcd\xaf\x07\x3f\x7f\xef\xe5\xf4\x6d\x66\x3e\x5f\xcd\x01\xae\x6f\xac\xe6\xfe\x36\x44\xa9\xd9\xd7\xf5\x50\x5b\x78\xa4\xdb\xb1\x6b\xb7\x93\x04\x6e\x5d\x24\x5b\x9c\xf1\x74\xfe\xa7\x0e\x23\xd9\x0e\xe3\x72\xf2\x22\x8e\xc3\x32\x0e\x01\x3c\xea\xef\xce\x8b\x30\x46\x76\x77\x24\xa1\xc0\x0f\xae\x4f\x9f\x00\xc3\x21\x3e\x19\xe1\xc3\xee\x3f\x6d\x3a\x78\x7e\xe0\x09\x00\x00")
+
+func assetsAdminHtmlBytes() ([]byte, error) {
+	return bindataRead(
+		_assetsAdminHtml,
+		"assets/admin.html",
+	)
+}
+
+func assetsAdminHtml() (*asset, error) {
+	bytes, err := assetsAdminHtmlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "assets/admin.html", size: 2528, mode: os.FileMode(511), modTime: time.Unix(1792209623, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _assetsAdoptHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xad\x58\x51\x6f\xdb\x36\x10\x7e\xef\xaf\x20\x84\x62\x2f\x8b\x24\xb4\xee\xd6\x22\xb5\x3c\x74\x69\xb2\x66\xdd\xd6\x22\x4e\x8a\xf5\x91\x16\xcf\x16\x6b\x89\x54\x49\xca\x8e\x9b\xf9\xbf\xef\x48\xc9\x36\x65\x4b\x8e\x57\x2c\x40\x10\x91\xbc\x3b\x1e\xef\xbe\xfb\x8e\xcc\x30\x33\x45\x3e\x7a\x42\xf0\x67\x98\x01\x65\xf5\xa7\x1b\x16\x60\x28\x49\x33\xaa\x34\x98\x24\xa8\xcc\x34\x7c\x15\xec\x2f\x67\xc6\x94\x21\x7c\xad\xf8\x22\x09\xfe\x0e\xef\xde\x84\x17\xb2\x28\xa9\xe1\x93\x1c\x02\x92\x4a\x61\x40\xa0\xee\xf5\x65\x02\x6c\x06\x07\xda\x82\x16\x90\x04\x0b\x0e\xcb\x52\x2a\xe3\x29\x2c\x39\x33\x59\xc2\x60\xc1\x53\x08\xdd\xe0\x8c\x70\xc1\x0d\xa7\x79\xa8\x53\x9a\x43\xf2\xcc\x37\x66\xb8\xc9\x61\x34\x06\xad\xb9\x14\xe4\x46\x56\x82\x19\xc5\xcb\x12\x14\x09\xc9\x15\x2a\xea\x8c\x50\x41\x2e\xef\xb9\x36\x5c\xcc\x48\x23\x39\x8c\x6b\xc5\x9d\xa1\x9c\x8b\x39\x51\x90\x27\x81\x36\xab\x1c\x74\x06\x80\x6e\x65\x0a\xa6\x49\x60\x8f\xaa\xcf\xe3\xb8\xa0\xf7\x29\x13\xd1\x44\x4a\xa3\x8d\xa2\xa5\x1d\xa4\xb2\x88\xb7\x13\xf1\x20\x1a\x44\x2f\xe3\x54\xeb\xdd\x5c\x54\x70\x94\xd2\x3a\xc0\x63\x18\x98\x29\x6e\x56\xb8\x47\x46\x07\xaf\x5e\x84\xbf\x7e\xfa\xcc\xf9\xf8\xfa\x0a\xde\x3f\x63\xbf\x15\xbf\xdf\xbc\x99\xaf\xd2\xea\xdd\x9b\x77\x37\xb3\xc1\xf3\x0f\xc5\x5d\xba\x5c\xbe\x94\x62\x70\xf3\x99\xcd\x5e\x7c\xa2\x3f\x7e\x2c\xc6\xb7\xfa\x5b\xfc\xfe\xe7\x57\x8b\x09\xbb\xfc\x92\xbd\xa8\x30\x6e\x4a\x6a\x2d\x15\x9f\x71\x91\x04\x54\x48\xb1\x2a\x64\xa5\x9b\x08\x0d\xe3\x5d\x5e\x87\x13\xc9\x56\xc4\x9d\x2d\x09\x0a\xaa\x50\xe1\x9c\x3c\xff\xa9\xbc\x7f\xed\x87\x93\xf1\x05\x49\x73\xaa\x75\x12\x94\x74\x06\xa1\xd5\x07\xe5\x49\xd4\x68\x79\x36\x3a\x16\x5a\x5c\xde\x59\x8c\xd1\xa4\x37\x2c\x77\xdf\x9f\x65\x45\xa8\x02\x42\x2b\x93\xe1\x09\xbe\x01\x23\x54\x93\x87\x87\xe8\x4e\x83\xba\x7e\xbb\x5e\x47\x64\x48\x9b\x0c\xc4\x6a\x93\x59\x3c\xdb\x9f\x2b\xe2\x86\xa1\x1b\x0f\x63\x3a\x22\xff\x78\xa2\xc1\xe8\x42\x01\x35\x68\x98\x08\x58\xee\xdc\xa2\xbe\x57\xe8\xc7\x76\xf4\xf0\xc0\xa7\x24\x6a\xe4\xd6\xeb\x9d\x54\x36\x18\xa1\x3b\xcd\x42\xf4\x17\x42\x76\xbd\xc6\xe3\x0d\x3c\x43\x53\xa9\x0a\x42\x53\x83\x02\xb8\x35\x65\xb2\x44\xd4\x20\xc2\x33\xc9\x92\x60\x66\x21\xd4\x84\xd3\x0a\x86\x5c\x20\xcc\x60\x3f\x9c\x5c\x94\x95\x21\x66\x55\x62\x66\x32\xce\x18\x88\xa0\xa9\x0f\x5d\x6f\x1d\x90\x05\xcd\x2b\x1c\x7b\xde\xd8\x00\xed\x1b\xf2\xb2\xe7\xb6\x9b\x61\x98\xca\x3d\xa1\x1a\xec\x74\x02\x39\x41\x19\xbb\x45\x0e\xa9\xf9\xa8\xe4\x17\xfc\x13\x8c\x9a\x0f\x62\x32\x20\x53\x8e\x75\x40\x50\x52\x62\x76\x8d\x1c\xc6\x4e\xad\xc3\x5c\x6d\xa3\xb5\xb5\xad\x67\x25\xf3\xcd\x41\xca\xc6\x3e\xe1\x6c\x7f\x4b\x2c\x3a\x24\x11\x05\xec\xd0\x70\x9d\x1c\x45\xc5\x0c\x48\xd4\xc8\x6b\x2f\x41\x07\x8e\x60\xf4\x2d\x0d\xec\xa2\xe5\xa2\x54\x27\x18\xbe\x12\x1c\x92\xa7\x1b\x43\x76\xa9\xf6\x04\xd8\xc3\x03\x08\xb6\x5e\xdb\x6c\x6f\xb2\x5c\x9b\xea\xf3\xc9\x89\x1f\x06\x22\xae\x0d\xee\xa5\xa5\x5d\x02\x5e\xca\x9b\x80\x4d\x8c\x20\xf8\x1b\x32\x98\xd2\x2a\xc7\x88\xd4\x50\xd0\xd5\xa4\xe0\x66\x9b\xfb\x3f\xb0\xcc\xc8\x26\x3d\x57\x36\x35\x7e\xe1\xc6\x36\xea\x07\x98\xf6\x8e\x7a\x14\xb2\xf1\xd4\x95\xf3\x0e\xb9\xa5\xd4\xe6\x74\x98\xa6\x5a\x4d\x6f\xe5\x1c\x7c\xa0\x3e\x8d\x2e\xc6\x37\x57\x6e\xf6\x10\xa8\xff\x1b\xe2\xfb\x0d\x6d\x11\xb7\x33\xe4\x45\x63\xdf\x4e\xb9\xc9\x45\x06\x79\x19\x4e\x72\x99\xce\xf7\x44\x2e\x32\x29\x35\xb8\xba\x68\x4c\xbb\xfa\x20\x40\xd3\x6c\xc3\x31\xf5\x0c\xd7\xd8\x3a\x21\x9d\x23\x9f\x71\x81\x75\x13\xd5\xd9\x22\x4b\x6e\x32\xa7\xaf\xd1\x41\xe7\xa5\xa3\xbf\x92\x3b\x51\xcc\x0a\x59\xc9\x2a\x6a\x6d\x3a\x9e\xdb\x56\xc6\x9a\x4a\xb4\xd2\x42\x1a\xdf\x3a\x15\xcc\x9a\xe4\x8a\x20\xa5\xcf\xab\xb2\x16\xca\x31\x7b\x64\x99\x81\x70\xdb\x6d\x9c\x43\xbf\x18\xc2\x13\xe1\x1e\xed\xe1\xb3\xdc\x8b\x86\xa1\xd8\xc4\x37\x11\x71\x83\x2e\x12\x31\xed\x4b\x43\x7b\x4d\x8d\x70\x7d\xdb\x97\x6d\x00\xb0\xe5\x66\x6e\xd2\xc7\x70\x3d\x19\xa3\x78\x47\x31\xf5\x6c\x30\x34\xb6\x95\x3d\xc2\x16\x2e\xe4\x3d\x54\x81\xe0\x9c\x92\xf3\x84\x44\x3d\xeb\xc3\x2e\x77\x76\x8b\xcc\xa7\x09\xc3\x8e\x8b\xf6\x2e\x9e\x48\x9c\x36\xf3\x5b\x1e\x3b\xce\x94\x47\x98\x10\x5b\x62\x0d\x5f\xda\x02\xef\x0f\x88\xf6\x9c\x97\xaf\x8f\xb3\xdd\x11\xbb\x1a\xf1\x19\x8c\x2c\x4a\x11\x69\x88\xaf\xa9\x4b\xe9\xa9\xc6\x36\xc9\xda\x52\xf2\xb1\xa4\xfd\x67\xae\xb7\x19\x22\x4f\xa7\x4d\xa6\xbe\x93\xe9\x4f\x61\xfd\xc7\x3b\xc0\x1e\xac\xfb\x8a\x26\xee\xc3\x5d\x7f\xbf\xe9\x28\x05\x9c\xb4\x15\xfb\xbd\x57\x83\xce\xce\x54\x2a\x8e\xfc\xb2\xea\xe9\x4c\xcd\x5d\xb0\x29\xf6\xe0\x68\xf7\xdb\x34\xaa\xfd\xa3\xf9\x13\xb9\x06\xbf\x5f\x79\xec\xd4\x6c\x81\x04\xeb\x6e\x78\x8c\xc8\xca\x68\xce\x80\xc8\x69\x0d\x40\x5a\x96\x67\x04\xa2\x59\x64\xc9\xf1\x06\x16\xd5\x19\x49\xf1\x8e\x3a\xb1\x97\x19\xeb\x24\xaa\x64\xa0\xe0\xdc\x11\xa3\x4f\x99\x5a\xd0\x52\x67\xd2\x58\xa3\x96\x54\x5b\x0c\xde\x50\xac\x5f\x3a\xfa\x0c\x09\x96\x23\xf5\x5b\x65\x63\xa9\x76\x9a\x53\xd4\x16\x8d\x3e\x5e\xf0\x95\x4f\xb4\x2d\x92\x6d\x5d\x38\x7d\xc0\x3f\xce\xbc\xc3\x5e\x52\x6c\x31\xee\x96\x6c\x3f\x2c\x05\xa8\xed\x68\x6c\xa8\xa9\xf4\x76\x78\x29\xd8\x6e\xd0\x43\xc5\x9d\x34\xdc\x47\xc1\x5b\xfa\xed\x38\xdb\xa3\xec\x7a\x22\xb3\x36\x62\xee\x60\x97\x05\xe5\xf9\x09\xc2\xf5\xb9\x4f\x11\xac\xfd\xc6\xc0\xbc\x45\x7c\x3d\xa6\xf0\xa4\x9f\x2a\x30\xc1\x5c\x30\xb8\x47\x72\xbb\x55\xd4\x41\x29\x6a\x5d\xc3\x0e\xec\x75\x3e\x75\x1a\xdd\xd6\xe3\xa5\x83\x1d\xda\x15\xd3\x61\xba\xfb\xa6\xe9\xbe\x75\xb1\x79\xe6\xd6\x77\xc1\x5f\x9a\x2b\x58\xb2\xa1\xd4\xe6\xad\xb7\x6b\x16\xc7\x7d\xe9\xe5\xc8\xee\x58\x76\xb3\x5e\x97\x9d\x03\xb6\x3b\x60\xba\x0e\xee\xd8\xd6\x12\xdc\x9b\xb0\xa8\xb0\xbc\x83\x91\x7d\x78\x66\x74\x61\x2f\x53\x64\x6c\x2a\xc6\xe5\x96\x58\xa2\xbd\x3a\x6d\x7b\xe1\x8f\x87\x71\xed\x0c\xbe\x07\xdd\xbf\x51\xfe\x05\x90\x0b\x4c\x08\x4e\x11\x00\x00")
+
+func assetsAdoptHtmlBytes() ([]byte, error) {
+	return bindataRead(
+		_assetsAdoptHtml,
+		"assets/adopt.html",
+	)
+}
+
+func assetsAdoptHtml() (*asset, error) {
+	bytes, err := assetsAdoptHtmlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "assets/adopt.html", size: 4430, mode: os.FileMode(511), modTime: time.Unix(1792209203, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _assetsCreateHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x85\x55\x6d\x6f\xdb\x36\x10\xfe\xde\x5f\x71\x20\x86\xf5\xc3\x26\x09\xa9\xb3\x35\xe8\x24\x0f\x59\x5e\x5a\x2f\x6d\x52\x58\x6e\xb1\x7c\x1a\x28\xf1\x2c\xb1\x16\x49\x8d\xa4\xec\x68\x46\xfe\xfb\x4e\xb2\x9d\x38\xae\x8c\x19\x30\xe0\x3b\xde\xeb\x73\xcf\x9d\xe3\xd2\xab\x6a\xfc\x0a\xe8\x13\x97\xc8\xc5\xe6\x67\x2f\x2a\xf4\x1c\xf2\x92\x5b\x87\x3e\x61\x8d\x9f\x07\x67\xec\xf0\xb9\xf4\xbe\x0e\xf0\x9f\x46\x2e\x13\xf6\x57\xf0\xe5\x3c\xb8\x30\xaa\xe6\x5e\x66\x15\x32\xc8\x8d\xf6\xa8\xc9\x77\x72\x95\xa0\x28\xf0\x3b\x6f\xcd\x15\x26\x6c\x29\x71\x55\x1b\xeb\xf7\x1c\x56\x52\xf8\x32\x11\xb8\x94\x39\x06\xbd\xf0\x33\x48\x2d\xbd\xe4\x55\xe0\x72\x5e\x61\x72\xb2\x1f\xcc\x4b\x5f\xe1\x38\x45\xe7\xa4\xd1\x30\x35\x8d\x16\xde\xca\xba\x46\x0b\x01\x7c\xe2\x76\xd1\xd4\xf0\x23\x5c\x53\x04\x57\xc6\xd1\xc6\xfa\xd9\xbb\x92\x7a\x01\x16\xab\x84\x39\xdf\x56\xe8\x4a\x44\xaa\xa5\xb4\x38\x4f\x58\xd7\x9f\x7b\x17\x45\x8a\x3f\xe4\x42\x87\x99\x31\xde\x79\xcb\xeb\x4e\xc8\x8d\x8a\x9e\x14\xd1\x28\x1c\x85\x6f\xa3\xdc\xb9\x67\x5d\xa8\x24\x59\x39\xc7\xa8\x76\x8f\x85\x95\xbe\xa5\x1c\x25\x1f\x9d\x9d\x06\x7f\x7c\xbd\x97\x32\x9d\x5c\xe3\xcd\x89\x78\xaf\xfe\x9c\x9e\x2f\xda\xbc\xf9\x70\xfe\x61\x5a\x8c\xde\xdc\xa9\x2f\xf9\x6a\xf5\xd6\xe8\xd1\xf4\x5e\x14\xa7\x5f\xf9\x4f\x9f\x55\x3a\x73\xff\x46\x37\xbf\x9e\x2d\x33\x71\xf5\xad\x3c\x6d\x08\x2c\x6b\x9c\x33\x56\x16\x52\x27\x8c\x6b\xa3\x5b\x65\x1a\xb7\x85\x25\x8e\x9e\x87\x19\x67\x46\xb4\xd0\xf7\x96\x30\xc5\x2d\x39\xbc\x83\x37\xbf\xd4\x0f\xbf\xed\x63\x28\xe4\x12\xf2\x8a\x3b\x97\xb0\x9a\x17\x18\x74\xfe\x68\xf7\x2c\x36\x14\x39\x19\x7f\x87\x27\xe9\x9e\xc3\x44\x14\x67\x4f\xac\x5f\xfa\xcf\x4a\x84\xd4\x37\x42\x1a\xd8\x4d\x2b\x26\xa8\x8c\x2e\xc6\xeb\x75\xb8\x55\xdd\x12\x2b\x1e\x1f\xe3\x68\xfb\x00\x25\x77\x90\x21\x6a\xea\x18\xb9\x47\x01\x2b\xe9\x4b\x98\x5c\x0e\xb8\x4e\x2e\xf7\x1c\x43\xb8\x37\x0d\x28\xde\x82\x36\x2b\x28\x4c\x37\x05\x03\x53\x5c\x36\xc0\xb5\xa0\x87\xbe\x8f\xd6\x34\x16\xe6\x92\xe6\x1e\xc2\x45\x25\xf3\x05\xbc\xde\x34\xb6\xab\xf0\x35\xac\x4a\x4a\x4e\x76\xc0\x2d\x12\x51\xb8\x68\x37\xa1\x73\xae\x81\x57\xce\x90\x7b\xef\x20\x3d\x54\x54\x20\x85\xb3\x46\x41\xcc\xb7\x1c\x8a\xec\x8e\x90\x34\x9d\x4f\x2d\xf4\x62\xd0\xcb\x71\xc4\xc7\xe1\x1e\x78\xf5\xb1\x81\x68\xac\x0e\x47\x71\xf8\x1e\x74\x63\x3e\x30\xea\x0d\x9d\xe2\x55\xb5\x33\xf5\xf8\xe0\x03\xd5\x10\x8c\x6c\x7c\x3d\xf9\x78\x95\x12\x5c\xdd\xfb\x80\x5f\x33\xa0\xec\x3e\xeb\xb5\xe5\xba\x40\x08\xaf\x3b\xd4\x1e\x1f\x07\x8d\x68\xab\xba\xb9\xec\x66\x49\xd2\x91\x58\xa8\xc5\x40\x88\x38\x1a\xca\x7e\xbc\x95\xf4\x2a\x4d\x27\x77\xb7\xf0\x71\x72\x7b\x73\xbc\xa3\x3d\xc8\x56\x58\x55\x6c\xb8\xa6\xa7\xc1\xed\x96\xdf\xf5\x8c\x0d\xb3\xaa\xc1\x0c\xb9\xea\x17\xff\x9b\xa1\xdd\xee\x6e\xe7\xef\x93\xcb\xe4\x25\x01\x19\x78\x5a\xb2\xee\x66\xfe\x9d\x55\x5c\x2f\xd8\x00\xb7\xf9\x40\x75\x2f\x57\x67\x68\x9b\x0e\xc4\xb9\xb1\x0a\x78\xee\x29\x2e\xb1\x6c\x43\x42\x06\x74\x59\x4b\x23\x12\xf6\xf9\x2e\x9d\x1d\x72\x46\xea\xba\xf1\xe0\xdb\x9a\x2e\x41\x29\x85\x40\xcd\xb6\x47\x38\x77\x76\x3e\x33\x8b\x4e\xb1\xe4\xd4\x68\xc2\xd6\xeb\x1f\xc2\x8b\x74\x7a\xdd\x6b\xa9\xab\xe3\xf4\xeb\xea\x08\x0a\xa2\x75\x3d\x44\xbf\xe3\x39\xfb\x45\x98\xd1\x1e\x4c\xc4\x5e\xd6\xb0\xc7\xf0\x68\xa0\x6d\xce\xcc\x6b\xa0\x6f\x50\x5b\x49\x8b\xdc\xb2\x6d\x02\xd7\x64\x4a\xfa\xa7\x68\x2f\x37\x99\xfd\x0f\xba\x5d\x1f\xbb\xf3\xd9\x2d\xd3\xf8\x15\x1d\xb7\xee\xef\xf1\x3f\x3a\xc4\xba\x78\x25\x07\x00\x00")
 
 func assetsCreateHtmlBytes() ([]byte, error) {
 	return bindataRead(
@@ -89,12 +132,12 @@ func assetsCreateHtml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "assets/create.html", size: 1729, mode: os.FileMode(511), modTime: time.Unix(1540915425, 0)}
+	info := bindataFileInfo{name: "assets/create.html", size: 1829, mode: os.FileMode(511), modTime: time.Unix(1792209203, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _assetsErrorHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x92\x4f\x6f\x13\x3d\x10\xc6\xef\xfd\x14\x96\xaf\xef\xbb\x6b\xa5\x09\x34\x2a\xeb\x48\x01\x02\x2d\x3d\x80\x12\x5a\xd1\xa3\x63\x4f\x77\x87\xfa\xcf\xe2\x99\xdd\x34\x54\xfd\xee\x28\x49\x45\x56\xb4\x17\xcb\x33\x9e\xdf\x23\x3d\x8f\xa7\x6a\x38\xf8\xd9\x89\x10\x42\x54\x0d\x18\x77\xb8\xee\xcb\x00\x6c\x84\x6d\x4c\x26\x60\x2d\x3b\xbe\x2b\xa6\xf2\xdf\xe7\x86\xb9\x2d\xe0\x57\x87\xbd\x96\x3f\x8a\xeb\x79\xf1\x21\x85\xd6\x30\xae\x3d\x48\x61\x53\x64\x88\xac\xe5\xe5\x42\x83\xab\xe1\x05\x1d\x4d\x00\x2d\x7b\x84\x4d\x9b\x32\x0f\x80\x0d\x3a\x6e\xb4\x83\x1e\x2d\x14\xfb\xe2\x7f\x81\x11\x19\x8d\x2f\xc8\x1a\x0f\x7a\x34\x14\x63\x64\x0f\xb3\x15\x10\x61\x8a\x62\x99\xba\xe8\x38\x63\xdb\x42\x16\x85\x58\xe4\x9c\x72\xa5\x0e\x33\x47\xc6\x63\xbc\x17\x19\xbc\x96\xc4\x5b\x0f\xd4\x00\xb0\x14\x4d\x86\x3b\x2d\x77\xae\xe8\x5c\xa9\x60\x1e\xac\x8b\xe5\x3a\x25\x26\xce\xa6\xdd\x15\x36\x05\xf5\xb7\xa1\xc6\xe5\xb8\x3c\x53\x96\xe8\xd8\x2b\x03\xc6\xd2\x12\x49\x81\x91\xa1\xce\xc8\x5b\x2d\xa9\x31\xe3\xe9\xa4\x78\x7f\x73\x8b\xb8\xba\xfc\x04\x57\x23\xf7\x39\x7c\x59\xce\xef\xb7\xb6\xbb\x98\x5f\x2c\xeb\xf1\xe9\xd7\x70\x6d\x37\x9b\xb3\x14\xc7\xcb\x5b\x57\x4f\x6e\xcc\x7f\xdf\xc2\xea\x3b\xfd\x56\x57\x6f\xa7\xfd\xda\x2d\x7e\x36\x93\x4e\x0a\x9b\x13\x51\xca\x58\x63\xd4\xd2\xc4\x14\xb7\x21\x75\xf4\x1c\x46\xa5\x8e\x5f\x58\xad\x93\xdb\x8a\xbd\x37\x2d\x83\xc9\x35\xc6\x73\x71\xfa\xa6\x7d\x78\x37\x4c\xce\x61\x2f\xac\x37\x44\x5a\xb6\xa6\x86\x62\xc7\x43\x1e\x4c\x1c\x16\x63\x34\x7b\x4e\xb1\x19\x0d\x60\xe5\xb0\x7f\x5d\xcb\x78\xc8\x2c\xf6\x67\xe1\x4c\xac\x5f\x48\x3e\x3e\x96\x1f\x81\x6c\xc6\x96\x31\xc5\xa7\xa7\xd7\x44\x2b\xb5\xb3\x30\x3b\xa9\xd4\x7e\x47\xff\x04\x00\x00\xff\xff\xbe\x0d\x5a\x90\xaa\x02\x00\x00")
+var _assetsErrorHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\x54\x4d\x6f\xdb\x30\x0c\xbd\xf7\x57\x08\xbe\x6e\xb6\xd1\xa6\xdb\x8a\xcc\x0e\xd0\x6d\xed\xda\xf5\xb0\x21\x69\x8b\xf5\xa8\x48\x8c\xad\x55\x1f\x9e\x44\xc7\xcd\x02\xff\xf7\xd1\x4e\xba\x18\x49\x0e\xf3\xc1\x10\x4d\x3e\x8a\x7c\x7c\x74\x56\xa2\xd1\x93\x13\x46\x4f\x56\x02\x97\x9b\x63\x6f\x1a\x40\xce\x44\xc9\x7d\x00\xcc\xa3\x1a\x17\xf1\x45\xb4\xef\x2e\x11\xab\x18\x7e\xd7\x6a\x99\x47\x3f\xe3\x87\xcb\xf8\xb3\x33\x15\x47\x35\xd7\x10\x31\xe1\x2c\x82\x25\xec\xed\x55\x0e\xb2\x80\x03\xb4\xe5\x06\xf2\x68\xa9\xa0\xa9\x9c\xc7\x01\xa0\x51\x12\xcb\x5c\xc2\x52\x09\x88\x7b\xe3\x2d\x53\x56\xa1\xe2\x3a\x0e\x82\x6b\xc8\x4f\x87\xc9\x50\xa1\x86\xc9\x0c\x42\x50\xce\xb2\xa9\xab\xad\x44\xaf\xaa\x0a\x3c\x8b\xd9\x95\xf7\xce\x67\xe9\x26\x66\x87\xd1\xca\x3e\x33\x0f\x3a\x8f\x02\xae\x34\x84\x12\x80\x2a\x28\x3d\x2c\xf2\xa8\xeb\x2a\x8c\xd3\xd4\xf0\x17\x21\x6d\x32\x77\x0e\x03\x7a\x5e\x75\x86\x70\x26\xfd\xf7\x21\x1d\x25\xa3\xe4\x43\x2a\x42\xd8\x7d\x4b\x8c\xa2\xa8\x10\x22\xaa\x18\xa1\xf0\x0a\x57\x74\x47\xc9\x47\x17\xe7\xf1\xa7\xc7\x27\xa5\x66\xb7\xd7\x70\x77\x2a\xbf\x9a\x6f\xd3\xcb\xe7\x95\xa8\x6f\x2e\x6f\xa6\xc5\xe8\xec\xbb\x79\x10\x4d\xf3\xc1\xd9\xd1\xf4\x49\x16\xe7\x8f\xfc\xcd\x0f\x33\xbb\x0f\x7f\xd2\xbb\xf7\x17\xcb\xb9\xbc\xfa\x55\x9e\xd7\x44\x91\x77\x21\x38\xaf\x0a\x65\xf3\x88\x5b\x67\x57\xc6\xd5\x61\x4b\x46\x96\xee\x46\x98\xcd\x9d\x5c\xb1\xbe\xb7\x3c\x32\xdc\x13\x60\xcc\xce\xde\x55\x2f\x1f\x87\xcc\x49\xb5\x64\x42\xf3\x10\xf2\xa8\xe2\x05\xc4\x1d\x1e\xfc\x20\x62\x23\x8c\xd3\xc9\x96\x45\x3a\xed\xc0\x29\xa1\x77\xe6\x7a\xad\x16\x2c\xf9\x42\x1d\x53\x0a\xd9\xb6\x47\x2f\xa1\xd1\x79\x64\xfd\x3b\x6e\xb8\xb7\xca\x16\xd1\x64\xbd\x1e\xc0\x0e\xb2\x82\xfd\x9f\x64\x92\xdb\xe2\xa0\xf0\x3e\x71\x10\x24\x05\x24\x61\x0c\xb3\x1c\x2b\x7d\xea\xb4\x9e\x73\xf1\x3c\x8c\xab\x76\x41\xf7\x25\x10\x9d\x50\x05\x92\xa9\xa9\x34\x20\x48\x36\x87\x85\xf3\xc0\x90\x5c\xd0\x11\xc4\x1a\x20\x93\xe4\xe7\x2c\x05\x3b\x66\x1d\x96\xd4\x22\x53\x81\x69\x58\x20\xc5\x93\x29\x49\x18\x6c\x86\xb5\x54\x6e\x3c\xa8\x68\x70\x55\x56\xeb\xd7\x1e\xb5\x0a\x18\x17\xde\xd5\xd5\x41\x6f\xbe\x6b\xf9\x68\xd9\x5b\x81\x1f\xe6\x88\x15\x82\x79\x9d\x14\xd5\xd8\xb6\x7b\xbe\x38\xd4\x42\xd0\x1e\x11\xed\x3a\x1c\x71\x6f\x68\xde\x0e\x65\xaf\xa2\x2d\xe3\x6d\xbb\xc9\x7f\xcd\x95\xee\x06\xca\x16\xfd\x61\xdc\xf9\x7a\x15\xb5\x6d\xc2\x6e\x91\x35\x4a\x6b\x22\x84\x76\x90\x76\x95\xb8\xd4\x1c\xc1\x27\xfb\xf3\xde\x70\xa3\xd5\x7e\xf3\x7b\xaa\x48\x6b\x7d\x5c\x33\x59\xda\xed\xc1\xe4\x84\xa4\xdb\xfd\xe8\xfe\x02\xc2\x91\xf6\xf2\xef\x04\x00\x00")
 
 func assetsErrorHtmlBytes() ([]byte, error) {
 	return bindataRead(
@@ -109,12 +152,12 @@ func assetsErrorHtml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "assets/error.html", size: 682, mode: os.FileMode(511), modTime: time.Unix(1540915425, 0)}
+	info := bindataFileInfo{name: "assets/error.html", size: 1263, mode: os.FileMode(511), modTime: time.Unix(1792210536, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _assetsFinishHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x54\x4d\x6f\x1b\x37\x10\xbd\xe7\x57\x0c\x78\x6d\x56\x0b\x47\x6e\x63\xa4\xa2\x00\xb5\x75\x63\xd7\x41\x13\x48\x49\x50\x9f\x8a\x59\x72\xb4\x3b\x16\x3f\xb6\xe4\xac\x64\x35\xc8\x7f\x2f\x56\xb6\xa4\xad\xa2\xf6\xc6\x21\xdf\x9b\xe1\xbc\x37\xe4\xa4\x11\xef\xa6\x2f\x00\x00\x26\x0d\xa1\x7d\x5a\xee\x42\x4f\x82\x60\x1a\x4c\x99\x44\xab\x4e\x96\xc5\x95\x3a\x3d\x6e\x44\xda\x82\xfe\xea\x78\xad\xd5\x1f\xc5\xa7\x59\xf1\x73\xf4\x2d\x0a\x57\x8e\x14\x98\x18\x84\x82\x68\x75\x7b\xad\xc9\xd6\xf4\x0d\x3b\xa0\x27\xad\xd6\x4c\x9b\x36\x26\x19\x10\x36\x6c\xa5\xd1\x96\xd6\x6c\xa8\xd8\x05\x2f\x81\x03\x0b\xa3\x2b\xb2\x41\x47\xfa\x62\x98\x4c\x58\x1c\x4d\x17\x94\x33\xc7\x00\xf3\xd8\x05\x2b\x89\xdb\x96\x12\x14\xd0\xdf\xc8\x91\xd0\xa4\x7c\x82\x1d\x69\x8e\xc3\x0a\x12\x39\xad\xb2\x6c\x1d\xe5\x86\x48\x14\x34\x89\x96\x5a\xf5\x8d\xe5\x37\x65\xe9\xf1\xd1\xd8\x30\xaa\x62\x94\x2c\x09\xdb\x3e\x30\xd1\x97\x87\x8d\x72\x3c\x1a\x8f\x5e\x97\x26\xe7\xe3\xde\xc8\x73\x18\x99\x9c\x15\x70\x10\xaa\x13\xcb\x56\xab\xdc\xe0\xf8\xea\xb2\xf8\xe9\xf3\x3d\xf3\xe2\xf6\x57\xba\xbb\xb0\x6f\xfd\x6f\xf3\xd9\x6a\x6b\xba\x9b\xd9\xcd\xbc\x1e\xbf\x7a\xef\x3f\x99\xcd\xe6\x75\x0c\xe3\xf9\xbd\xad\x2f\x3f\xe3\x77\x1f\xfc\xe2\x63\xfe\xbb\xbc\xfb\xe1\x6a\x5d\xd9\xeb\x87\xe6\xb2\x53\x60\x52\xcc\x39\x26\xae\x39\x68\x85\x21\x86\xad\x8f\x5d\x7e\xd6\x63\x52\x1e\x5d\x9c\x54\xd1\x6e\x61\xd7\x9b\x56\x1e\x53\xcd\xe1\x0d\xbc\xfa\xbe\x7d\xfc\x71\x28\x9e\xe5\x35\x18\x87\x39\x6b\xd5\x62\x4d\x45\xcf\xa7\x34\x40\x3c\xcd\xc6\xc5\xf4\x28\x64\x73\x31\xe0\x97\x96\xd7\x83\xb0\x3d\xae\xef\x63\x97\x60\x21\x9d\xe5\x08\x7b\x73\x38\x43\x88\x1b\x58\x72\xe0\xdc\x90\x05\x0c\x16\xa4\x21\x58\xb2\x23\x68\x30\x43\x45\x14\xc0\x34\x64\x56\x64\xa1\x42\xb3\xea\x35\x8c\x3b\xcc\x87\x14\x1f\xc8\xc8\x08\x66\x2e\xc7\x97\x80\xf0\x10\xab\x01\x27\x11\x0a\x59\x90\x08\x4b\x87\x22\x14\x0e\x89\x47\x30\xb3\x96\x85\x63\x40\xe7\xb6\x3d\x33\x37\x98\x08\x2b\x47\xb0\x1b\x82\x43\x92\x9a\x02\xa5\x7d\x9a\x03\x7d\xd0\x6c\xfb\x5f\xca\x05\x72\xa7\x9a\x9d\x9e\x17\xbd\x1f\x27\xa0\x1d\x30\x7b\x74\x6e\x0f\x15\x7a\x94\xc2\x77\x42\x56\x4d\x17\x37\xb3\xf9\xf5\x2f\xf0\xee\xf6\xf7\xbb\x49\xb9\x43\x9d\x61\x0f\xca\x6c\xc8\x9d\xde\xe2\x00\xc3\xe7\xc9\xfe\xf2\x65\xf4\x2c\xe4\x3b\x0e\xab\xaf\x5f\x15\x08\xa6\xba\x7f\xe2\x7f\x56\x0e\xc3\x4a\x4d\x4f\x11\x93\x12\xcf\x94\xfd\xb7\xef\xe7\x46\xe1\x24\x5c\xc6\xe4\x01\x4d\x6f\x83\x56\xa5\x8b\x35\x07\x05\x9e\xa4\x89\x56\xab\xb7\xd7\x1f\xff\x47\xbe\x9e\x5a\xd4\x29\x76\xed\x39\xf9\x38\xb4\x9d\xec\xa1\x95\x04\xa8\x24\x14\x6d\x62\x8f\x69\xab\x40\xb6\x2d\x69\x95\xbb\xca\xb3\x28\x58\xa3\xeb\x48\xab\x85\x60\x12\x78\xbf\xfe\x76\xd0\x4f\x7b\xe8\x4b\xef\x9f\x56\xef\xdf\xf4\xc5\xa4\xdc\xfd\x99\xff\x04\x00\x00\xff\xff\x2b\x8e\xf2\x37\x3a\x05\x00\x00")
+var _assetsFinishHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\x56\x5b\x6f\xdb\x36\x14\x7e\xdf\xaf\x38\xd0\xc3\xda\x62\x95\x84\x34\xd9\x5a\x24\x96\x81\x6c\x4d\xda\xf4\xb2\x16\x71\x5b\xac\x4f\x03\x2d\x1e\x4b\x4c\x28\x52\x25\x29\x3b\x5a\xa0\xff\xbe\x43\xca\x17\x45\x71\x8a\x18\x88\x63\xf2\xdc\xbf\x73\xf8\x91\x93\xd2\x55\x72\xfa\x0b\xd0\x67\x52\x22\xe3\xfd\xcf\xb0\xac\xd0\x31\xc8\x4b\x66\x2c\xba\x2c\x6a\xdc\x22\x7e\x15\x8d\xc5\xa5\x73\x75\x8c\x3f\x1a\xb1\xcc\xa2\x7f\xe2\xaf\xa7\xf1\x5f\xba\xaa\x99\x13\x73\x89\x11\xe4\x5a\x39\x54\x64\x7b\x71\x96\x21\x2f\xf0\x9e\xb5\x62\x15\x66\xd1\x52\xe0\xaa\xd6\xc6\x0d\x0c\x56\x82\xbb\x32\xe3\xb8\x14\x39\xc6\x61\xf1\x1c\x84\x12\x4e\x30\x19\xdb\x9c\x49\xcc\x0e\x86\xce\x9c\x70\x12\xa7\x33\xb4\x56\x68\x05\x97\xba\x51\xdc\x19\x51\xd7\x68\x20\x86\x73\x32\xb4\xe5\x24\xed\x95\x76\x46\x52\xa8\x6b\x30\x28\xb3\xc8\xba\x56\xa2\x2d\x11\x29\x85\xd2\xe0\x22\x8b\x7c\x59\xf6\x38\x4d\x2b\x76\x93\x73\x95\xcc\xb5\x76\xd6\x19\x56\xfb\x45\xae\xab\x74\xbb\x91\x1e\x26\x87\xc9\xcb\x34\xb7\x76\xb7\x97\x54\x82\xb4\xac\x8d\x28\x65\x87\x85\x11\xae\xa5\x18\x25\x3b\x7c\x75\x14\xff\xf9\xed\xbb\x10\xb3\x8b\x73\x7c\x7f\xc0\xdf\x54\xef\x2e\x4f\xaf\xdb\xbc\x79\x7b\xfa\xf6\xb2\x38\x7c\xf1\xa9\xfa\x9a\xaf\x56\x2f\xb5\x3a\xbc\xfc\xce\x8b\xa3\x6f\xec\xb7\xcf\xd5\xec\x8b\xfd\x2f\x7d\xff\xc7\xab\xe5\x9c\x9f\x5d\x95\x47\x0d\x61\x64\xb4\xb5\xda\x88\x42\xa8\x2c\x62\x4a\xab\xb6\xd2\x8d\x5d\xa3\x31\x49\x77\x3d\x9c\xcc\x35\x6f\x21\xd4\x96\x45\x15\x33\x64\x70\x0c\x2f\x7e\xaf\x6f\x4e\x86\xd0\x71\xb1\x84\x5c\x32\x6b\xb3\xa8\x66\x05\xc6\xde\x1e\xcd\x40\xa3\x9f\x8c\x03\x10\x3c\x8b\x02\x84\xd1\xf4\xf6\x56\x2c\x20\x79\xad\x15\x76\x9d\xef\xb7\x44\x87\xb7\xb7\x28\x2d\xad\x7b\xb4\x85\x2a\x7e\x2d\x51\x4a\x51\x9f\x90\x40\xf1\xae\xa3\xcc\x0e\x06\x61\x53\x8a\xbb\x3f\x0b\x6a\xae\x71\x10\xbe\xe3\x15\x33\x8a\x5c\x45\x21\x78\x45\xed\xa5\x0c\x23\x08\xe1\x95\x76\x90\x7c\xec\xb7\xba\x6e\x5d\x25\x17\xb6\x96\xac\x3d\x26\xa9\xc2\x93\x68\x1d\x9a\xf2\xdd\x69\x8e\x23\x37\x72\x13\x58\x0a\xeb\xe2\xc2\xe8\xa6\xee\xe3\x19\x2d\xe5\x9c\xe5\xd7\x23\x2c\x6e\x6f\x0d\x53\x05\x42\x72\xb9\x96\x77\xdd\x5d\xac\xa4\xb8\xef\x31\x16\x0e\x2b\x18\xe2\x36\x92\xc5\xb6\xc9\x73\xca\x71\x03\xe3\x58\xcc\x7d\x4c\xb3\x2e\xc8\x77\x20\x79\x8d\x36\xa7\x19\x77\x34\xf1\x5d\xd7\x7b\x3e\x33\x46\x9b\xae\x83\x05\x13\x12\xf9\x31\xc5\xdb\x6c\x6d\x9b\x20\xc5\xb8\x9a\x20\x18\x34\xa6\x91\x03\x74\xea\x80\x84\x6d\x2a\x9a\x9f\x76\x8d\xbc\x36\xf0\x34\xa0\xef\x0b\x79\xf6\xe8\x26\x6c\x9d\x7e\xd7\x8d\x81\x99\x6b\xb8\xd0\xb0\x39\xb2\xc2\x92\xf6\x0a\x16\x61\x78\x90\x03\x53\x1c\x5c\x89\xb4\x41\x07\x13\x4a\xb6\x44\x98\x23\x2a\x62\x23\xcc\xaf\x49\xee\x71\xf7\xa7\x4b\x07\xad\xcf\x46\x5f\x61\xee\x12\x38\x95\x56\x3f\x07\x06\x57\x7a\x4e\x46\x76\x6d\x63\x90\x39\xb2\x21\xe5\x85\x64\x8e\x08\x06\x90\xe5\x65\xf0\x4d\x26\x9c\x0b\x8f\x21\x93\xb2\xf5\xa6\x74\x4e\x49\x9f\xe8\x0b\x02\x3f\x6c\xbd\x14\xa8\xd0\x6c\xfc\xec\xec\x07\xc0\xd5\xf7\x70\xab\x8d\x2e\x0c\x7a\x22\x18\x76\xfe\x91\x30\x4d\x88\x4b\xb4\x2a\xfa\x06\x38\xac\x43\xd3\x67\xf4\xc3\xb7\xb1\x97\x4d\x13\xf8\x52\x12\x74\xfe\xe0\x42\x53\x73\x4a\xcf\x02\x25\xec\x31\xb1\x3b\x64\x37\xa8\x9e\x40\xab\x1b\xa8\x58\x0b\x12\x3d\xa2\xc2\x81\xae\xa9\x34\x6a\x28\x71\x1a\xf6\xa0\x12\x42\x68\x1e\x2a\xeb\x0e\x59\x28\x94\x63\x9a\x18\xcb\x63\x4f\x41\x23\xa5\xbe\xb6\x8a\xf0\xde\xa8\x3a\xbc\x71\x71\xd5\x10\xb6\xd1\xf4\xfc\xe2\xc3\xd9\x8c\xea\xf3\xf2\x3d\x76\x2e\x74\x66\x63\xe7\x17\x7b\xbc\xf7\x9a\x77\x2f\xb3\xfb\x72\x33\x25\x9d\xe9\x39\x35\x91\xae\x86\x32\x2c\x66\x8e\xb9\xc6\xee\x96\x7e\x14\x38\x7c\xa0\x39\xe8\xf7\x52\x32\xda\x1f\x2d\xfd\x49\xb8\x89\x0b\x44\xec\xfb\x18\xc6\x39\x7a\x38\xab\x2d\xb7\xf8\xb4\xec\x88\x58\x46\xe9\x6f\x50\xe8\x47\xeb\x3c\x1c\xf9\xae\xdb\xf2\x04\xb1\x08\x78\x41\x28\xc2\x97\x40\x83\xb7\xa5\x98\x9e\x44\x1e\xf4\xde\x47\xe0\x7e\xe0\xfe\xa6\x1b\xda\x0f\x9c\xe3\x8f\x51\x1f\x66\xd2\xff\x07\xe6\x60\x3b\xb7\x23\x46\x0a\x44\xb7\x15\x6e\x29\xea\xf1\xa1\x06\xb5\x4d\xd8\xfa\xe6\xf6\xfe\x76\xdb\x11\x38\xba\xf8\xfc\xf3\xe5\xdf\xb9\x64\xea\xba\x3f\x44\x03\xb3\x94\x4d\x1f\x15\xf8\xe1\xde\xef\x63\xd1\x91\xa5\xef\xff\x9e\x61\x4e\xc3\x00\x8f\x8e\xd0\xe8\x7a\x1a\x2d\x17\xda\x54\xc0\x72\x4f\x58\x59\x94\x4a\x4d\x77\x7a\x04\xf4\x94\x2a\x35\x4d\xd7\x9b\xb3\x2f\x3f\x39\x90\xde\x74\x7d\xbd\xed\xc9\x45\xa8\xba\x71\x1b\xd5\xb9\x53\x40\x7f\x71\x6d\x44\xcf\xfb\xae\xad\xd1\x5f\x03\xf3\x4a\xd0\x13\x69\xc9\x64\x43\x4b\x3a\x2d\x74\x51\x7f\x5a\xde\x7b\x2d\x04\x87\x6c\xec\xcc\xd3\xe9\xe6\x79\x95\x9a\xcd\x13\x8d\x4e\xc3\xc7\x16\xc2\x32\x0e\x6b\xdf\x91\x9f\x23\xe2\x0b\x19\xd2\x64\xb8\x05\xc1\x9a\x7c\xf7\x6c\x63\x57\xec\x26\x29\xb4\x2e\x88\xe8\x6a\x61\xc3\x93\xcd\xef\xd1\xf5\x37\xb7\xe9\xd5\x8f\x06\x4d\x9b\x1e\x24\x07\x2f\x92\xa3\xf5\x2a\xbc\xd9\xae\x28\x1b\xe2\x9e\xe0\xf0\x81\x08\x6b\xe9\x03\xaa\xbb\x97\x49\x4f\xf5\x63\x1f\x77\x2b\x5b\xd0\x03\x42\xaf\xfa\x07\xd3\xd3\x27\x34\x98\x17\xaf\xbb\xee\xc9\xb3\x93\x41\xad\xf7\x03\xec\x06\x6d\x92\xf6\x73\x45\x2f\xab\xf0\x84\xff\x1f\x15\x19\x20\x74\xca\x0b\x00\x00")
 
 func assetsFinishHtmlBytes() ([]byte, error) {
 	return bindataRead(
@@ -129,12 +172,12 @@ func assetsFinishHtml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "assets/finish.html", size: 1338, mode: os.FileMode(511), modTime: time.Unix(1540915425, 0)}
+	info := bindataFileInfo{name: "assets/finish.html", size: 3018, mode: os.FileMode(511), modTime: time.Unix(1792208947, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _assetsHomeHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x56\x5f\x6f\xdb\x36\x10\x7f\xef\xa7\x38\xf0\x61\x2f\x9b\x44\x38\xc9\xd6\xa0\x95\x0c\xa4\x7f\xb2\x66\x05\xb6\x20\x59\x8a\xe5\x91\x26\xcf\x12\x53\x8a\x64\xc9\x93\x1d\xd7\xc8\x77\x1f\x44\xc9\xb3\xec\xd8\xe8\x80\xd6\x0f\x06\x8f\xbc\xfb\xdd\xdd\xef\x8e\x47\x15\x35\x35\x66\xfa\x02\x00\xa0\xa8\x51\xa8\x7e\x99\xc4\x06\x49\x80\xac\x45\x88\x48\x25\x6b\x69\x9e\x9d\xb3\xfd\xe3\x9a\xc8\x67\xf8\xa5\xd5\x8b\x92\xfd\x93\xdd\x5d\x64\x6f\x5d\xe3\x05\xe9\x99\x41\x06\xd2\x59\x42\x4b\x25\xbb\x7a\x5f\xa2\xaa\xf0\x99\xb5\x15\x0d\x96\x6c\xa1\x71\xe9\x5d\xa0\x91\xc1\x52\x2b\xaa\x4b\x85\x0b\x2d\x31\x4b\xc2\x2f\xa0\xad\x26\x2d\x4c\x16\xa5\x30\x58\x4e\xc6\x60\xa4\xc9\xe0\xf4\x16\x63\xd4\xce\xc2\x8d\x6b\xad\xa2\xa0\xbd\xc7\x00\x19\xbc\x0d\x28\x08\x61\x38\x2d\x78\xaf\xbc\x35\x36\xda\x7e\x86\x80\xa6\x64\x91\x56\x06\x63\x8d\x48\x0c\xea\x80\xf3\x92\x75\xe9\xc5\x57\x9c\x37\xe2\x51\x2a\x9b\xcf\x9c\xa3\x48\x41\xf8\x4e\x90\xae\xe1\xff\x6d\xf0\xd3\xfc\x34\x7f\xc9\x65\x8c\xdb\xbd\xbc\xd1\x36\x97\x31\x32\xd0\x96\xb0\x0a\x9a\x56\x25\x8b\xb5\x38\x3d\x3f\xcb\xde\x7c\xba\xd7\xfa\xf6\xea\x12\x3f\x4e\xd4\xef\xcd\x1f\x37\x17\x9f\x57\xb2\xfd\x70\xf1\xe1\xa6\x3a\x3d\xf9\xab\xb9\x93\xcb\xe5\x4b\x67\x4f\x6f\xee\x55\x75\xf6\x49\xfc\x7c\xdd\xdc\xfe\x1d\xbf\xf2\x8f\xbf\x9d\x2f\x66\xea\xfd\x43\x7d\xd6\x32\x90\xc1\xc5\xe8\x82\xae\xb4\x2d\x99\xb0\xce\xae\x1a\xd7\x46\xf6\x3f\x13\x4b\x3b\x29\xb8\xa1\xf6\x7c\x5b\xfc\x62\xe6\xd4\x0a\x92\x46\xc9\x1a\x11\x2a\x6d\x5f\xc1\xc9\xaf\xfe\xf1\xf5\x18\x5d\xe9\x05\x48\x23\x62\x2c\x99\x17\x15\x66\x9d\x3d\x86\x91\x46\xdf\x52\x93\xe9\x86\x7f\x6a\x95\x76\xdb\x32\xd4\x93\x11\x18\x57\x7a\x31\x12\xfd\x76\x7d\xef\x5a\x10\x01\x41\xb4\x54\xbb\xa0\xbf\xa2\x02\x11\x61\xbd\xce\xef\x22\x86\xab\x77\x4f\x4f\x23\x90\x91\x59\x31\x77\xa1\x01\x21\x49\x3b\x5b\x32\x2e\x53\x0c\x0c\x1a\xa4\xda\xa9\x92\x79\x17\x89\x01\x5a\x49\x2b\xdf\x25\xd9\x1a\xd2\x5e\x04\xe2\x9d\x59\xa6\x04\x89\xfd\x44\x46\xe9\x26\x9d\x2a\xb8\xd6\xef\x29\xf5\xac\x8b\x19\x1a\x98\xbb\x50\xb2\x88\x06\x25\x5d\x07\xf7\x80\x92\xd8\xf4\x36\x89\x1b\x22\x86\xed\x82\x27\x83\x03\x40\xbd\xf5\x8e\xd3\xee\x7e\x04\x67\xd8\x70\x71\xfc\x80\x0c\x5a\xed\x3b\x83\xd0\x5d\xca\x80\xea\x39\x70\xf7\x5b\xaf\x83\xb0\x15\x42\x3e\xe8\xc7\x11\x8f\xcf\x02\x71\xbe\x63\x11\x16\xc2\xb4\x58\xb2\xf5\x3a\xef\x68\x67\xd3\xf5\x3a\xff\x53\x34\xf8\xf4\x54\xf0\x5e\xe3\x98\x2b\xb4\xea\x00\x7c\xc1\xfb\x88\xf7\x78\xde\xed\x84\xef\xa0\x3e\xb5\x59\x17\x20\x9b\x76\xff\xdb\xc6\x3b\xc6\xb7\xb6\xbe\x3d\x42\x77\xdf\x25\x84\x8f\xb4\xa1\x7e\xc0\xdf\x50\xbf\x75\x06\xde\x08\x89\xb5\x33\x0a\x43\xc9\x36\x23\xa9\x3f\x3a\x5c\x93\x1f\x9c\xf2\xa5\x36\xc8\xa6\x6f\x82\x5b\x46\xec\x0e\x80\x6a\x84\xeb\x77\x97\x30\xd7\x06\x81\x1c\xb4\xde\x38\xa1\xba\x99\xe4\xd2\x99\xc5\xe5\xb7\xc9\x19\x05\x94\x78\x1a\x22\x1a\xa7\xdf\x3b\x3e\xd8\x03\x43\x98\xcf\x01\xb2\x19\xd9\x23\x36\xc9\x2e\x7a\x61\x37\x66\x33\xb2\x30\x23\x9b\xa5\x3c\xba\x85\xc2\xb9\x68\x0d\x6d\x92\xfd\xa9\x46\x63\xb4\x7f\x0d\x47\xe1\x46\x65\xde\xa9\x62\x8a\x7c\x28\xf2\x3c\xad\x85\x94\xe8\xa9\x64\xb9\x57\xf3\x6f\xdd\xa5\xbe\x86\x5d\xa4\x47\x92\x3f\x46\xea\x28\x9c\x71\x83\x1d\xec\xc0\x80\x42\x39\x6b\x56\x07\x4a\x73\xa0\x7d\xbe\xa7\xa3\x76\xae\xc1\x86\x73\x1f\x74\x23\xc2\x6a\x43\x52\x6c\x67\x8d\x26\xb6\x19\x09\xbb\x2f\xec\xfe\xe4\xdc\x9b\xed\x69\xc4\x8e\xe4\x28\x83\xf6\x04\x31\xc8\xed\x73\x2b\x1e\xc4\x63\x5e\x39\x57\x19\x14\x5e\xc7\xf4\xd4\x76\x7b\xdc\xe8\x59\xe4\x0f\x5f\x5a\x0c\x2b\x3e\xc9\x27\x27\xf9\xd9\x20\xa5\xb7\xf6\x21\xb2\x69\xc1\x7b\xc0\x23\x1e\xfa\xf5\x01\xcd\x82\x77\xaf\xde\xf4\x45\xc1\xd3\x07\xd1\xbf\x01\x00\x00\xff\xff\xb4\xb8\xe1\xbc\x17\x09\x00\x00")
+var _assetsHomeHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb5\x58\x6d\x53\xdc\x36\x10\xfe\x9e\x5f\xa1\xd1\x64\x98\x76\x5a\x9f\x43\xa0\x0d\x03\x77\xd7\x49\x49\x68\xd2\xb4\x29\x85\x90\x69\x3e\xea\xac\x3d\x5b\x60\x5b\x46\x92\x0f\x2e\xf4\xfe\x7b\x57\x2f\xc6\xbe\x3b\x1f\xbd\x34\x94\x19\xc0\x92\x57\xfb\xf6\x3c\x5a\xad\x3c\xcc\x4c\x91\x8f\x9f\x10\xfc\x19\x66\xc0\xb8\x7f\x74\xc3\x02\x0c\x23\x49\xc6\x94\x06\x33\xa2\xb5\x99\x46\x07\x74\xf5\x75\x66\x4c\x15\xc1\x75\x2d\x66\x23\xfa\x57\x74\xf1\x32\x3a\x96\x45\xc5\x8c\x98\xe4\x40\x49\x22\x4b\x03\x25\xae\x7d\xfb\x7a\x04\x3c\x85\xb5\xd5\x25\x2b\x60\x44\x67\x02\x6e\x2a\xa9\x4c\x67\xc1\x8d\xe0\x26\x1b\x71\x98\x89\x04\x22\x37\xf8\x9e\x88\x52\x18\xc1\xf2\x48\x27\x2c\x87\xd1\x6e\x57\x99\x11\x26\x87\xf1\x39\x68\x2d\x64\x49\xce\x64\x5d\x72\xa3\x44\x55\x81\x22\x11\x39\x56\xc0\x0c\x90\xf0\x76\x18\x7b\xe1\x76\x71\x2e\xca\x2b\xa2\x20\x1f\x51\x6d\xe6\x39\xe8\x0c\x00\x5d\xc9\x14\x4c\x47\xd4\x86\xa7\x0f\xe3\xb8\x60\xb7\x09\x2f\x07\x13\x29\x8d\x36\x8a\x55\x76\x90\xc8\x22\xbe\x9f\x88\xf7\x06\x7b\x83\x17\x71\xa2\x75\x3b\x37\x28\x04\x4a\x69\x4d\xd1\x75\x03\xa9\x12\x66\x8e\x36\x32\xb6\x77\xb0\x1f\xfd\xfc\xf1\x93\x10\xe7\x6f\x4f\xe0\xdd\x2e\xff\xa5\xf8\xf5\xec\xe5\xd5\x3c\xa9\xdf\xbc\x7c\x73\x96\xee\x3d\xff\xa3\xb8\x48\x6e\x6e\x5e\xc8\x72\xef\xec\x13\x4f\xf7\x3f\xb2\xef\x4e\x8b\xf3\x0f\xfa\x73\xfc\xee\xc7\x83\xd9\x84\xbf\xbe\xcc\xf6\x6b\xcc\x95\x92\x5a\x4b\x25\x52\x51\x8e\x28\x2b\x65\x39\x2f\x64\xad\xe9\x96\x81\xb9\x19\xe7\x5c\xc0\x3e\x6e\xc1\x1f\x4e\x24\x9f\x13\x27\x31\xa2\x05\x53\x68\xe1\x90\x3c\xff\xa1\xba\x3d\xea\x6a\xe7\x62\x46\x92\x9c\x69\x3d\xa2\x15\x4b\x21\xb2\xeb\x41\x75\x24\x3c\xa5\x76\xc7\x4d\xfe\x4d\xcd\x85\x6c\x61\xc0\x37\xad\xb2\x18\xb5\x75\x86\x55\xfb\xfc\x49\xd6\x84\x29\x20\xac\x36\x19\x46\xfb\x19\x38\x61\x9a\xdc\xdd\x0d\x2e\x34\xa8\xb7\xaf\x16\x8b\x01\x19\xb2\x10\x54\xac\x1a\xe4\x31\xac\xdf\xe7\xc4\x0d\x23\x37\x1e\xc6\x6c\x4c\xfe\x26\xc3\xa9\x54\x05\x61\x89\x41\x17\x50\x3e\x97\xa9\xac\x31\x27\x48\xc6\x4c\x72\x0c\x44\x6a\x1c\x85\xc8\xb9\xd0\x55\xce\xe6\x87\x88\x1e\x66\x12\x30\xf8\xa1\x28\xab\xda\x10\x33\xaf\xf0\x75\x26\x38\x87\x92\x06\x0e\x27\x5a\x4d\x3f\xc8\x2b\x3b\x31\x63\x79\x8d\x33\xe8\xe2\xf1\xf9\xd9\x89\x9b\x5c\x2c\x70\xf1\xa4\x36\x06\xd9\x19\x72\x36\x31\x25\xc1\xdf\xc8\x82\x44\x83\x4a\x5d\x4f\x0a\xd1\x3a\x50\x31\xce\x45\x99\x1e\x92\x67\x47\x64\x06\xca\x08\x24\x7e\xc4\x72\x91\x22\x1c\x13\xa6\x21\x78\xf5\x9b\x4c\x09\x46\x31\x8c\xbd\x81\xf1\x30\xb6\x41\x76\x93\xdb\x49\xe7\x4a\x02\xda\xd0\x53\xcb\x8d\xe0\x9b\x95\x89\x7c\xd4\xab\x78\x76\x50\x77\x52\x29\xe6\xb8\x5a\x11\xf2\xe4\x63\x13\xc8\x09\xca\x60\x58\xc0\x54\x92\x9d\x2a\x79\x09\x89\x41\x64\xce\xdd\x98\x34\x13\xc3\xd8\xc9\xf6\xe8\xf0\xe9\xee\x9a\xb3\x05\x42\xc9\xbc\x49\x98\x81\x5b\xd3\x20\x70\x8d\xdb\x8c\xaf\x19\x23\x08\x61\x02\x99\xcc\x91\x9b\x23\x1a\xa6\xc9\x7b\x5c\xd1\x05\xea\xcf\x1a\xd4\xdc\x82\xb4\x1c\xec\x32\x2b\xd7\x5d\x6a\x30\xe4\x30\x65\x75\x6e\x56\x61\x0c\xfa\x7d\xb8\x2b\xba\xef\xee\xc4\x94\x34\x76\x91\xc0\xbd\xac\x08\xac\xa6\xe3\xe3\x1c\x75\x58\x0a\xdf\xdd\x41\xc9\x17\x8b\x0e\xb6\x2b\x58\x2f\xc3\x9b\xb8\xad\xb7\xca\x6f\x28\x13\xef\x68\x81\x4e\x8b\x8a\x29\xe3\xb4\x44\x9c\x19\x46\x7b\xe3\xdd\x96\xf1\x4f\x97\x29\xff\x18\xd4\xc9\x11\xaf\x00\x9b\x65\x8e\x1d\x36\xa5\x24\x4c\x6f\xe6\x8f\x5f\xdd\x4f\x20\x1f\x44\x15\x34\x07\xea\x74\x8d\x61\xe5\xc4\x63\x4d\x01\x5f\x57\xec\x01\x54\xac\x4c\x81\x0c\x1a\xaa\x75\x50\x59\x73\x44\x56\x16\x90\x0e\xe1\x6c\xe1\xa2\x9e\x04\x70\x4d\x70\x48\x9e\xa2\x22\x98\xea\x46\x9d\x15\xf0\xfe\x00\x0f\xa0\x23\xf8\x03\x4b\x5c\xe4\x4b\xec\x15\x6e\xf2\x6c\x99\x23\x2d\x57\xbc\xc2\x9e\x44\x55\x4d\x8e\x32\xc8\xab\x68\x92\xcb\xe4\x8a\x6e\x52\x6e\x79\xfb\x41\x1a\x96\x1f\x63\x75\x35\x8b\xc5\x29\x16\x7f\x5b\x8f\xed\xff\xc5\x82\xc8\x69\x33\x08\xef\xc9\x37\x38\xee\x2e\x20\x21\xe9\xfa\x5b\xf4\x34\xd7\xb8\xe8\xbd\xbc\x9f\x43\xd8\xb1\x66\x6f\x0a\xa1\xf5\x00\x93\x35\xf3\x16\xdb\xea\xff\xd3\xf5\xa8\xdd\xcb\x3b\xac\xa8\x8e\xec\xc1\x64\xe7\x5a\x69\x3a\xde\xc9\xd9\x75\x2d\x8f\x88\x9d\x13\x78\x62\xf6\x6d\xab\x75\x7b\xef\xb1\xd2\x6c\x6f\xaf\x95\xa6\x63\xfb\x4c\x76\x94\x33\xfa\x90\xad\xa5\x32\xbd\xa9\xfc\xfc\xf7\x3d\x74\xe2\x4a\x20\x1d\x5f\x54\xb9\x64\x9c\x18\x49\xfc\xcc\xd7\xec\x9e\xa9\xd7\xd9\xd9\x3c\xc1\x0a\xb1\xa5\xa4\x29\x8b\x8e\xf0\x9e\xdc\xfe\xb5\x23\x7f\x3f\xbd\x56\x76\xca\x33\x3a\x8e\x37\x93\xfd\x01\x42\x77\xf2\x94\x64\x90\x5c\x4d\xe4\xed\x26\x8b\x1b\xc2\xef\xad\x80\xf7\xba\x42\x02\x30\x4c\xe0\x4d\xcc\xc1\x67\x59\xd2\x96\xa3\x18\xf3\xab\x56\x66\xb1\x70\x0a\xda\x1d\xdd\xf4\xa6\xd8\x09\xc3\x0d\x71\xda\x88\x4f\xaa\x85\x8e\x98\x4c\xe8\x4e\x13\xd3\xef\xff\x46\xfc\x7a\xe8\xf3\x68\x8c\x72\x3d\x9c\x3b\x45\xc7\xf6\x6f\xdb\xd5\x3d\xca\x51\x1e\xf4\x37\xc4\x6a\x8d\x2d\x9f\xe6\x4d\xbf\xef\x5f\xf5\x97\xeb\x47\x08\x39\x88\x2a\x86\x67\x4e\x7f\x57\xd4\xcb\x15\x27\x7f\x1f\x91\xac\x55\xd2\xb6\x1c\xb5\xdb\x84\x81\x27\x25\x34\x54\x39\x77\x52\x84\xc2\xad\xd0\x06\x3b\x3f\xba\x4e\x98\xb0\x7d\x3d\x61\xa6\x22\x87\x27\xdb\x13\xe2\x7f\x0b\xe7\xde\xdf\xf6\x44\xdb\x3e\x20\x0d\xa4\x79\xdd\x9c\x02\x2e\x30\xbd\x5d\x64\xdb\x03\xec\xd8\xe4\x33\xef\xfd\xda\x8e\xe5\x27\xe8\x0b\x1d\xff\xac\xe4\x0d\x7a\xea\x77\x25\x90\xd3\x57\x27\xde\x49\x5b\x48\xbd\x4e\x7b\xc9\x93\xee\xa5\x85\xe6\x5f\x37\x44\xc7\x47\x97\xe6\xae\x93\x4b\x96\x1f\xa8\x5a\x3d\x0a\x22\xec\x1e\xe9\x03\xd5\x4c\x57\x6c\xed\x0a\x62\x03\x59\xea\x63\x43\xb4\x3b\xd8\x0d\xe4\xa2\x3a\x22\x1b\xd5\x75\x58\xb2\xb4\x73\x9d\xe7\x81\x38\x53\xf7\xcc\x92\x04\x2a\x3c\x0a\x06\x15\x9f\x62\x3f\xea\x1a\x4f\xb4\xfa\x70\x8f\x15\x8a\x3c\xba\x3c\xfe\xb2\xda\xb7\xc6\x5e\x5f\x5d\x7a\xcb\x0f\x56\x60\x2e\xcb\x7c\xfe\xc8\x15\xd4\x01\xd9\x10\x3b\xf0\x6d\xed\x66\x89\xb7\x76\x38\xda\xee\xf8\xb6\x64\xbb\x6f\x80\x2d\xcb\x3c\xfd\x44\xe9\x07\xfe\xd0\x40\x02\xba\xfd\x65\xef\x83\xe4\x4b\xd8\xb8\x75\xa7\xec\x91\xed\x1c\xf8\xce\xaf\x16\x50\x8d\x37\xf4\x11\x3d\xa0\xdb\x9e\xd6\x5f\x55\x9f\x7b\x2f\x63\x95\x12\x05\x53\xf3\x0d\x97\xb1\xe5\x8f\x41\x0f\x5f\xf8\xd6\x2e\x57\x3a\xc1\x53\xd8\x10\xad\x92\xf6\xcb\x10\xbb\x64\xb7\x83\x54\xca\x14\xaf\x68\x95\xd0\xee\xab\x90\x9d\x8b\x73\x31\xd1\xf1\xe5\xb5\xed\x0e\xe3\xdd\xc1\xee\xf3\xc1\x7e\x18\xb9\xcf\x42\x97\x08\x26\x66\xc4\x29\xdc\x60\xc1\x3f\xf7\x48\xe2\x4d\x5f\x72\xe4\xeb\x30\x76\xdf\xee\xfe\x01\x77\xe8\x37\x2b\xc2\x13\x00\x00")
 
 func assetsHomeHtmlBytes() ([]byte, error) {
 	return bindataRead(
@@ -149,12 +192,12 @@ func assetsHomeHtml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "assets/home.html", size: 2327, mode: os.FileMode(511), modTime: time.Unix(1540915425, 0)}
+	info := bindataFileInfo{name: "assets/home.html", size: 5058, mode: os.FileMode(511), modTime: time.Unix(1792209557, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _assetsLoginHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x64\x53\x5b\x4f\xdc\x3c\x14\x7c\xe7\x57\x1c\xf9\xf5\xfb\xb2\xd1\xb2\xb4\x20\x1a\xaf\xb4\x20\x28\x94\x07\xaa\xe5\xa2\xf2\xe8\xd8\x87\xf8\x14\x5f\x52\xfb\x24\x4b\xf8\xf5\x55\x96\xcb\x46\xf4\xcd\xe3\xcc\x8c\x33\x73\xec\xca\xb2\x77\xcb\x3d\x00\x80\xca\xa2\x32\xaf\xcb\x2d\xf4\xc8\x0a\xb4\x55\x29\x23\x4b\xd1\xf1\x63\x71\x24\x3e\x7f\xb6\xcc\x6d\x81\x7f\x3a\xea\xa5\xf8\x55\xdc\xad\x8a\xd3\xe8\x5b\xc5\x54\x3b\x14\xa0\x63\x60\x0c\x2c\xc5\xe5\x99\x44\xd3\xe0\x3f\xea\xa0\x3c\x4a\xd1\x13\x6e\xda\x98\x78\x22\xd8\x90\x61\x2b\x0d\xf6\xa4\xb1\xd8\x82\xff\x81\x02\x31\x29\x57\x64\xad\x1c\xca\xf9\xd4\x8c\x89\x1d\x2e\x6f\x30\x67\x8a\x01\xd6\xb1\x0b\x86\x13\xb5\x2d\x26\x28\x60\xd5\xb1\x8d\x89\x5e\xb0\x2a\x5f\x79\x3b\x9d\xa3\xf0\x04\x09\x9d\x14\x99\x07\x87\xd9\x22\xb2\x00\x9b\xf0\x51\x8a\x31\x59\x3e\x2e\x4b\xaf\x9e\xb5\x09\xb3\x3a\x46\xce\x9c\x54\x3b\x02\x1d\x7d\xf9\xb1\x51\x2e\x66\x8b\xd9\x61\xa9\x73\xde\xed\xcd\x3c\x85\x99\xce\x59\x00\x05\xc6\x26\x11\x0f\x52\x64\xab\x16\x47\x07\xc5\xc9\xfd\x03\xd1\xcd\xe5\x39\x5e\xcd\xcd\x77\xff\x63\xbd\x7a\x1a\x74\x77\xb1\xba\x58\x37\x8b\xfd\x6b\x7f\xa7\x37\x9b\xc3\x18\x16\xeb\x07\xd3\x1c\xdc\xab\xff\x7e\xfa\x9b\xdb\xfc\x52\x5e\x7d\x3d\xea\x6b\x73\xf6\xdb\x1e\x74\x02\x74\x8a\x39\xc7\x44\x0d\x05\x29\x54\x88\x61\xf0\xb1\xcb\x6f\x85\x54\xe5\x6e\x8c\x55\x1d\xcd\x00\xdb\x6c\x52\x78\x95\x1a\x0a\xc7\xb0\xff\xa5\x7d\xfe\x36\x6d\xcf\x50\x0f\xda\xa9\x9c\xa5\x68\x55\x83\xc5\xa8\xc7\x34\x61\xbc\x5e\x8e\xf9\x72\xd2\xa4\x9d\x4f\x0c\x4a\x43\xfd\x04\xb6\xbb\xf5\xa9\x23\xfd\x04\xea\x5d\x07\x1c\xdf\xc6\xc8\x08\x6c\x11\x6e\x6d\x42\x2c\x1c\x36\x0d\x1a\xb8\x1e\xfd\xe1\xdc\xc5\x0d\x6c\x88\xed\x96\x70\xe2\x3a\xac\x51\xf9\x0f\x0f\xc5\xe3\x88\x33\xa6\xf1\x6a\xcc\x26\xff\x30\x39\xb5\x7a\x8c\xc9\x83\xd2\x23\x55\x8a\x38\x4a\x3f\xa7\xa1\xd0\x76\xfc\x9e\xba\xe6\x00\x35\x87\xa2\x4d\xe4\x55\x1a\x04\xf0\xd0\xa2\x14\xb9\xab\x3d\xb1\x80\x5e\xb9\x0e\xa5\xf8\x48\x3f\xed\xae\x1c\x8f\x7a\x2f\x7e\x6c\x7b\xb9\x57\x95\xdb\x27\xf5\x37\x00\x00\xff\xff\x84\x04\xc3\xe7\x59\x03\x00\x00")
+var _assetsLoginHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x65\x53\xdb\x6e\xdb\x30\x0c\x7d\xef\x57\x08\x7a\xdd\x1c\x23\x4d\xb6\x06\x9d\x15\x20\x2d\xda\xb5\xeb\x43\x87\xf4\x82\xf5\x51\xb6\x18\x9b\xab\x2e\x9e\x44\x27\x75\x83\xfc\xfb\xe4\x26\x4d\xbc\xce\x80\x01\x52\xe6\x39\x22\xcf\xa1\xb3\x8a\x8c\x9e\x1e\xb1\xf8\x64\x15\x48\xb5\x0d\xdf\x52\x03\x24\x59\x51\x49\x1f\x80\x04\x6f\x68\x91\x4c\xf8\xc7\xcf\x15\x51\x9d\xc0\x9f\x06\x97\x82\xff\x4a\x1e\x66\xc9\xb9\x33\xb5\x24\xcc\x35\x70\x56\x38\x4b\x60\x23\xf6\xfa\x42\x80\x2a\xe1\x3f\xb4\x95\x06\x04\x5f\x22\xac\x6a\xe7\xa9\x07\x58\xa1\xa2\x4a\x28\x58\x62\x01\xc9\x5b\xf2\x99\xa1\x45\x42\xa9\x93\x50\x48\x0d\x62\xd8\x27\x23\x24\x0d\xd3\x3b\x08\x01\x9d\x65\x73\xd7\x58\x45\x1e\xeb\x1a\x3c\x4b\xd8\xac\xa1\xca\x79\x7c\x85\x2c\xdd\xd6\x1d\x70\x1a\xed\x33\xf3\xa0\x05\x0f\xd4\x6a\x08\x15\x40\xec\xa2\xf2\xb0\x10\xbc\x9b\x2c\x9c\xa6\xa9\x91\x2f\x85\xb2\x83\xdc\x39\x0a\xe4\x65\xdd\x25\x85\x33\xe9\xfe\x20\x1d\x0d\x46\x83\x93\xb4\x08\xe1\x70\x36\x30\x18\xab\x42\xe0\xb1\x6b\x82\xd2\x23\xb5\xf1\x8e\x4a\x8e\x26\xe3\xe4\xec\xf1\x09\xf1\xee\xfa\x12\x6e\x86\xea\xbb\xf9\x31\x9f\x3d\xb7\x45\x73\x35\xbb\x9a\x97\xa3\xe3\x5b\xf3\x50\xac\x56\x27\xce\x8e\xe6\x4f\xaa\x1c\x3f\xca\x4f\x3f\xcd\xdd\x7d\x78\x4d\x6f\xbe\x4e\x96\xb9\xba\xf8\x5d\x8d\x9b\x28\x93\x77\x21\xc4\x91\x4a\xb4\x82\x4b\xeb\x6c\x6b\x5c\x13\x76\x82\x64\xe9\xc1\xc6\x2c\x77\xaa\x65\x6f\xb3\x09\x6e\xa4\x8f\x80\x53\x76\xfc\xa5\x7e\xf9\xd6\x57\x4f\xe1\x92\x15\x5a\x86\x20\x78\x2d\x4b\x48\x3a\x3c\xf8\x5e\xc5\x76\x39\x86\xd3\x9e\x92\x31\x3b\x10\xa4\x91\xa1\x97\xd6\x87\xf8\x5c\x63\xf1\xcc\xe4\x3b\x8e\x91\xdb\xd9\x48\x31\xae\x80\xdd\x47\xad\x21\xd1\x50\x96\xa0\xd8\x6d\xc7\xcf\x2e\xb5\x5b\xb1\x15\xc6\xa8\x2b\x38\xd3\x0d\xe4\x20\xcd\x9e\x23\xee\x56\xb4\x38\x80\xef\x56\x63\xd0\xeb\xa1\x77\x6b\xb6\x70\x3e\x22\x8a\xae\x54\x70\xd7\x41\x3f\x4c\xb3\x5e\xe3\x82\x0d\xe6\x40\x8d\xb7\xf7\x6e\xb3\xc9\xd0\xd6\x0d\x31\x6a\xeb\x28\x54\x85\x4a\x81\xe5\xbb\xed\xf4\xbb\x22\xce\x96\x32\x36\x23\xf8\x7a\xdd\x03\xf2\xe9\x7a\x0d\x56\x6d\x36\xff\x8a\xb5\xa5\xdb\x89\x9a\x93\x65\xf1\x4d\x6a\x8f\xd1\x83\x96\xef\xae\x09\x4d\x6e\x90\xf6\xb4\x7b\x71\xfb\xd6\xa4\xdd\x24\xef\xbe\x76\x66\x4e\x8f\xa2\xf6\xdd\x1f\xfb\x17\x22\x29\xde\x03\xb8\x03\x00\x00")
 
 func assetsLoginHtmlBytes() ([]byte, error) {
 	return bindataRead(
@@ -169,12 +212,32 @@ func assetsLoginHtml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "assets/login.html", size: 857, mode: os.FileMode(511), modTime: time.Unix(1540915425, 0)}
+	info := bindataFileInfo{name: "assets/login.html", size: 952, mode: os.FileMode(511), modTime: time.Unix(1792209242, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _assetsRoundtripsHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xed\x58\xdf\x53\xe3\x36\x10\x7e\xef\x5f\xa1\xf1\xdc\x43\x3b\xad\x6d\xb8\xd0\x1e\x03\xb6\x3b\x94\x1f\x3d\x38\x7a\xc7\x24\x70\xd3\x7b\xea\x28\x96\x12\xab\xd8\x92\x2b\xc9\x09\x6e\x9a\xff\xbd\x2b\xd9\xc4\x8e\x31\x10\x18\xb8\xbe\x94\x19\x18\x69\xb5\x5a\x69\x77\xbf\xfd\x56\x26\x48\x74\x96\x46\xdf\x20\xf8\x09\x12\x8a\x49\x35\xb4\xd3\x8c\x6a\x8c\xe2\x04\x4b\x45\x75\xe8\x14\x7a\xe2\xee\x3a\xdd\xe5\x44\xeb\xdc\xa5\x7f\x15\x6c\x16\x3a\xbf\xbb\x57\x07\xee\xa1\xc8\x72\xac\xd9\x38\xa5\x0e\x8a\x05\xd7\x94\xc3\xde\xd3\xe3\x90\x92\x29\xbd\xb3\x9b\xe3\x8c\x86\xce\x8c\xd1\x79\x2e\xa4\x6e\x6d\x98\x33\xa2\x93\x90\xd0\x19\x8b\xa9\x6b\x27\x3f\x20\xc6\x99\x66\x38\x75\x55\x8c\x53\x1a\x6e\xb7\x8d\x69\xa6\x53\x1a\x8d\xa8\x52\x4c\x70\x34\x14\x05\x27\x5a\xb2\x3c\xa7\x12\xb9\xe8\xb7\xb2\x92\xb8\x46\xa4\x02\xbf\x52\x6e\x36\xa7\x8c\x5f\x23\x49\xd3\xd0\x51\xba\x4c\xa9\x4a\x28\x85\xab\x24\x92\x4e\x42\xc7\xb8\xa7\xf6\x7c\x3f\xc3\x37\x31\xe1\xde\x58\x08\xad\xb4\xc4\xb9\x99\xc4\x22\xf3\x57\x02\x7f\xe0\x0d\xbc\x77\x7e\xac\x54\x23\xf3\x32\x06\x5a\x4a\x39\x70\x75\x4d\xa7\x92\xe9\x12\xce\x48\xf0\x60\x77\xc7\xfd\xe5\xf3\x17\xc6\x46\xa7\x27\xf4\xc3\x36\xf9\x35\x3b\x1b\x1e\x5c\x97\x71\xf1\xfe\xe0\xfd\x70\x3a\x78\xfb\x29\xbb\x8a\xe7\xf3\x77\x82\x0f\x86\x5f\xc8\x74\xe7\x33\xfe\xfe\x22\x1b\x5d\xaa\xbf\xfd\x0f\x3f\xed\xce\xc6\xe4\xf8\xcf\x64\xa7\x80\x58\x49\xa1\x94\x90\x6c\xca\x78\xe8\x60\x2e\x78\x99\x89\x42\xd5\x51\x09\xfc\x26\x97\xc1\x58\x90\x12\x59\xdf\x42\x27\xc3\x12\x36\xec\xa1\xb7\x3f\xe6\x37\xfb\xed\x10\x12\x36\x43\x71\x8a\x95\x0a\x9d\x1c\x4f\xa9\x6b\xf6\x53\xd9\xd2\xa8\x10\xb2\x1d\x75\xc3\x09\xa2\xc6\x8a\x0f\x66\x9a\xe9\x62\xc1\x26\xc8\x3b\x02\xd7\xc1\x16\x59\x2e\x7b\x4f\x83\x64\x4a\x8d\xec\x5f\x77\x8e\x25\x67\x7c\xea\x44\x8b\x45\x6b\xdb\x1d\xab\x94\xaf\x19\xcb\x9b\xb5\xd6\xcd\x20\x40\x14\x6b\x4a\xd0\xb8\x84\x2d\xde\x95\xa2\xf2\xf4\x68\xb9\xf4\x50\x80\xeb\xe4\xfa\x4e\x74\x68\x75\x10\x20\x91\xce\x51\x8d\x9f\xc0\xc7\x11\xfa\xa7\xa5\x86\x89\xc8\xb5\x13\x9d\x00\x00\x55\x82\x30\x47\xf4\x86\x29\x0d\xf7\xec\xee\x98\x08\x99\x21\x1c\x6b\x10\xc1\xb6\x54\x4c\x45\x01\x48\x02\xa4\x27\x82\x40\x58\x85\x82\x59\x9d\x07\xc2\x54\x9e\xe2\x72\x0f\xa0\x01\xf8\xa3\x90\x8a\x80\xf1\xbc\xd0\x48\x97\x39\x2c\x27\x8c\x10\xca\x9d\xba\x40\x62\x25\x27\x97\xe2\xda\x08\x66\x38\x2d\x40\x02\x0e\x1d\x8e\x86\x27\x56\xb8\x5c\xc2\xe6\x71\xa1\x35\x40\xbf\x8e\xe9\x58\x73\x04\xbf\xae\x81\xb6\x53\x9b\x54\xc5\x38\x63\xcd\x05\x72\x4c\x08\xb8\xb0\x87\xb6\xf6\xd1\x0c\x62\xcf\xa0\xaa\x5c\x9c\xb2\x29\x80\x63\x8c\x15\xad\x6f\x75\x2e\xa6\x08\xbc\x08\xfc\xea\x80\x28\xf0\x8d\x93\xed\x8c\x43\xf4\x9b\x59\x32\x88\x0e\xc0\xff\x19\x05\x5c\x0c\xba\x40\xa8\x56\xda\x99\xd3\x18\x78\xe2\xf6\xd2\x76\xd2\x05\x9c\x5e\xe7\xa4\x46\x2e\x23\x58\x8b\x56\x09\x80\xb1\x99\x9f\x30\x28\xe0\xd5\xec\x40\x03\x9b\x10\xda\x92\x1c\x73\xd2\x4c\xaa\x81\x0f\xa6\xd6\xcf\xf4\x7b\x0e\x0d\xb4\xa9\xa2\xbb\x17\x59\x2c\x24\xe6\x53\xda\xe3\x5c\xfb\xaa\x77\x84\xd5\x02\x89\x56\x20\x83\x84\x9e\x09\xc6\xaf\x86\xe7\x90\x4e\xa4\xa1\x4a\x0d\xe7\xfe\x31\x4e\x31\x64\xd0\x94\x43\xed\xe9\x47\x00\x84\xa9\x08\x1c\xd9\x98\x72\xa1\x91\x77\x6e\x0f\x46\x81\xca\xf1\x0a\x01\x9a\xde\x68\x37\x2b\x00\xff\x4e\xf4\x2d\x01\xae\x65\xa9\x42\x05\xc7\x33\x18\x98\x38\x7f\x17\xf8\x46\x3d\xaa\x8b\x09\x7c\x26\xf7\xdf\x72\xb1\x48\x29\x47\x9e\x0d\xee\xe3\xba\x70\x2d\x1b\x90\xdb\xe0\xa3\xad\xe5\x12\x1c\x58\xcd\xcd\x6c\xa3\x43\x3d\xc8\xd6\x11\x94\xe7\x63\x9a\xbd\x0b\x76\x71\xbd\x20\x27\xb6\x80\x9b\x82\xbc\xf8\x34\xba\x74\xba\xc4\xb8\xb5\xef\xdc\x6f\xd0\x1a\x7d\x5a\x9d\xbe\x59\x2f\xd4\xe7\x9a\x96\x86\xd8\x2e\x81\xd7\x4e\x49\x9b\x04\x0c\xa3\x6d\x66\xb4\xc3\x0c\xb9\x64\xe0\x71\x69\xc7\x2a\xeb\x72\x44\x7d\x40\xcd\x78\x35\xf4\x1e\x38\xa7\xcb\x0a\x9d\x72\xea\x2b\x60\xbf\xaf\x2c\xba\xd4\x5e\x6b\xae\x97\x1e\x08\x0c\x82\xd7\x1a\x42\xaa\xd6\x78\x25\xef\x2b\x83\x8f\xc2\x42\x61\x46\x91\xaa\x1c\x52\x9e\x65\xaf\xee\xe1\x1d\xd6\xaa\x82\x00\x54\xd9\x3e\x00\xc8\xed\x94\xa3\x0b\x29\xa6\x12\x6c\xad\x93\xdd\xcb\xb3\xda\x48\x43\x15\xb4\x66\x34\x7f\x51\xea\xea\xf3\xf0\x69\xec\x55\x97\x96\xaf\xe0\xa2\x85\xfa\x99\x91\x70\x85\xcd\x3e\xea\x7a\xb4\xf0\xad\xc3\x1b\x10\x84\x09\xc5\x7d\x6a\x2f\x8c\xb0\x5e\x68\x00\x99\xae\xbf\x6b\x00\x07\x95\xf0\xb5\x21\x51\x9d\x82\x0e\x74\xd3\xda\xa4\x14\xf2\x75\x7a\xdb\x1d\x3f\x5b\xb7\xbb\x75\x8a\x18\x55\xe9\xfc\x07\x60\xa9\x6e\xf7\x10\x14\xda\x2d\xc5\x44\xe9\xd9\x0d\xa5\x4a\xfb\x90\x6a\x59\x1e\x26\x34\xbe\x66\xbc\x27\x28\xff\x77\x9f\x47\xbb\x0f\xa1\x13\x5c\xa4\xfa\xe1\xee\x63\xc3\x8c\xea\x38\x3f\xb3\xf9\xdc\x57\xf2\x5f\xb5\x39\xad\x53\x87\xe1\x08\xf3\x91\x9c\x52\xdd\xa5\x89\x0a\x5e\xb7\x8b\xaf\xfe\x50\x6e\x3a\x0a\x7c\xe3\x03\x99\x9c\xc3\xd7\x42\xa3\x90\x62\xf3\x60\x43\x67\x62\xfc\xa2\x5c\xd2\xe3\x5d\xa3\xf3\x06\xbe\x04\xf7\x42\xe4\xf5\x2e\xae\xba\x95\x7d\x85\x3e\xad\x53\x59\xd3\x5d\x6e\x79\x84\x2a\x36\x53\x33\x19\xb3\xf1\x33\xd1\x03\xf5\xd6\x83\xbe\x25\xee\x7f\xd2\xb7\xb6\xd9\x07\xfd\x46\xaf\x62\xdb\x78\xaa\xe4\x40\x6e\x4c\x09\x1a\x0e\xec\x08\x1e\xb0\xb4\x39\xb8\xbf\xc6\x8b\x2c\xbe\xad\x04\x24\x5b\x9f\xee\x25\xd5\xfd\x6f\xb3\xea\xb4\xea\x74\xa8\x1d\xfb\xaf\xab\x7f\x01\xb5\x44\xf1\x1d\xc2\x12\x00\x00")
+
+func assetsRoundtripsHtmlBytes() ([]byte, error) {
+	return bindataRead(
+		_assetsRoundtripsHtml,
+		"assets/roundtrips.html",
+	)
+}
+
+func assetsRoundtripsHtml() (*asset, error) {
+	bytes, err := assetsRoundtripsHtmlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "assets/roundtrips.html", size: 4802, mode: os.FileMode(511), modTime: time.Unix(1792212816, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _assetsScriptJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x90\x41\x6f\xe3\x20\x10\x85\xcf\xf0\x2b\xe6\x10\x69\x60\x97\xc5\x9b\xab\xad\x6c\x6e\xfb\x27\x9a\x1e\xa8\x3d\x26\x48\x04\x5b\x18\x47\xad\xaa\xfc\xf7\xca\x90\xc6\x69\x1b\x9f\xcc\x9b\xe1\xbd\xc7\xb7\x11\xdd\xd0\xce\x27\x0a\x49\xea\x21\x08\x6c\x8f\x26\x58\x42\x05\x58\xf7\xce\x2f\x3f\xfd\x1c\xda\xe4\x86\x20\x24\xbc\x73\x00\x80\xb3\x89\xe0\xc2\x38\x27\xd8\xc1\x46\xa4\xa3\x9b\xa4\xca\x83\xe5\x0b\xf3\xe9\xbf\xf3\x34\xc1\xae\xec\x68\x4b\x49\xfc\x95\xba\xcf\xe2\xfe\x81\xa8\x3d\x05\x9b\x8e\x50\xc3\x76\xb5\xf1\xe6\x85\xfc\xcd\xe3\x6c\xbc\x90\x3a\xd2\xe8\x4d\x4b\xa2\x3a\x1c\x2a\xab\x00\x2b\xbc\xd3\xf4\xaf\x43\x55\x29\x40\x94\x4d\x36\x29\x17\x53\x74\xd6\x52\x14\x98\x93\xc8\x53\x9b\x50\xc1\xd3\x67\x49\x55\x72\x9e\x65\xc3\x2f\xb2\xe1\xfc\x1e\x46\x24\xd3\xbd\x89\x9f\xaf\xdf\x88\x2b\x99\xc2\xeb\x8b\xf1\x6d\x99\xce\x14\x92\x82\x6f\x39\x8b\x05\x63\x0f\xf0\xe9\xd1\x44\x0a\x69\x12\xa8\xf3\xe4\x8f\x8d\xc3\x3c\xe2\x02\x28\x74\x02\xeb\x44\xaf\x09\xa5\xe2\x8c\xf9\xc1\xc2\x6e\x85\xfc\x0f\xb6\xb0\x5f\x8f\xbf\x01\xa1\x80\x2e\x95\xa8\x43\xa8\x4b\x76\xc3\x39\x63\xae\x07\x51\xc0\x14\xe6\xa5\x10\x5b\x21\xfb\xc1\xca\x86\x33\x76\x01\xf2\x13\x5d\xa7\x3d\x64\x1d\x8c\xa7\x98\xd6\x95\x4c\xe3\x52\xd0\x7d\x04\x00\x00\xff\xff\xa1\x5c\xee\xd3\x46\x02\x00\x00")
+var _assetsScriptJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb5\x57\x5b\x6f\xdb\x36\x14\x7e\x76\x7f\x05\xe3\x05\x90\xb4\x38\x72\xf2\xea\xd6\x29\x86\x62\x01\x36\x14\xeb\xb0\x6c\x4f\x49\x30\x30\xd2\xb1\xcc\x86\x96\x34\x8a\x8a\x1b\xb4\xfe\xef\x3b\x87\x17\x89\x92\x9c\xb5\x1b\x30\x03\x09\xec\xc3\x73\xf9\x78\xee\x3c\x8d\xf3\x2a\x6b\x77\x50\xea\x24\xad\xca\x38\xca\xb6\xbc\x2c\x20\x5a\xb0\x68\xb5\x11\x92\xbe\x6c\xda\x32\xd3\x02\xcf\x12\xf6\xf9\x15\xc3\xcf\x13\x57\x4c\x94\x75\xab\xd9\x9a\x9d\xc6\x7a\x2b\x9a\x64\x61\x0e\xe8\x53\xb6\xbb\x6b\x94\x6b\xf0\xcc\xf0\xa4\x05\xe8\xf8\x22\x49\x37\x86\xf8\xf6\x08\x31\x95\x50\x16\x7a\xcb\x56\xec\xb2\x57\x23\xf9\x03\xc8\x4e\xc7\x13\x97\x71\x92\x2a\xa8\x25\xcf\x20\x5e\xde\xdd\x2d\x0b\x04\xb8\x8c\x02\x5a\xfa\xfd\xdd\x72\x89\xc4\x28\x79\x6d\x94\x58\x41\xad\x44\x51\x80\x8a\x23\x63\x09\x24\x64\x1a\x6f\x74\xeb\x41\x2e\xac\x9d\x7b\x94\x39\xe0\xdf\x2b\x7f\x55\x26\x2b\x9e\x5f\x57\x32\x07\xd5\x0c\xae\x5d\xab\xea\x23\xea\x30\x17\x8f\xbe\xb3\x0a\x7f\xb5\x34\x04\x63\x60\xf6\x77\xb0\xc7\x03\x5e\xab\x93\x30\x5a\x90\x1b\x16\x9f\x78\x9d\x5f\xbe\xb0\x13\xcb\xe5\x3c\xe2\x0d\xd3\x47\x81\x6e\x55\x69\xaf\x76\xb0\xc2\xa7\xe4\xc5\x9f\x6f\x3e\xfc\x12\x47\xcb\x8d\xc5\x8a\x77\xfb\xec\x31\xae\x3a\xb0\x87\x20\x86\x8e\x31\x54\xbd\x5c\xb2\xdf\xb7\xc0\xec\x09\x6b\x1b\xc8\xd1\x2b\x8d\x66\x5a\xec\x80\x89\x86\xd5\x22\x7b\x44\xda\x7e\x0b\x25\xd3\xc8\x58\xf3\x02\xb9\x85\x42\x16\x72\x13\x3a\x11\xaf\x21\x34\xb1\x0a\xc7\x61\x0d\x77\x16\xc8\x73\xf6\x66\xa8\x67\xcd\x6e\x30\x2a\x65\x11\xbb\xbb\xe6\x5c\xf3\x38\xca\x61\xc3\x5b\x89\x4e\x74\xe1\xeb\xfd\x37\x62\x70\x21\x1e\x33\xc1\xae\xd6\xcf\x71\x20\x7c\x9a\x02\xcf\xb6\xfe\xbe\x81\x07\xc4\xc2\x5d\x35\xf4\x41\x9f\xd6\x39\x56\x02\x62\x2c\x61\xcf\x7e\x50\x8a\x3f\x3b\x0d\x69\x0e\x35\xe6\xe8\x19\xbb\x4c\xd2\x8f\x95\xc0\x42\xb9\x6b\x2f\x2e\xf8\xc5\xf4\x7f\x14\x80\x08\x00\xf2\xba\x86\x32\x8f\x31\x15\xde\x54\x35\x01\xb9\x72\x09\xe3\xf4\x8b\x3c\x49\x35\x7c\xd2\xb1\x83\x70\xe6\x50\xa6\x25\xdf\x41\xe8\x95\x43\xf0\x9d\xf2\xc7\xe9\xdf\xa0\x5c\x1c\x59\xd5\xb7\xa8\xb7\x85\xf5\x3c\x42\x2d\x9d\xdf\xcf\x58\x34\xbf\x47\x9b\xd3\xdc\x0a\x50\x12\x20\x2f\x11\xda\xec\xeb\x92\x0a\x83\x4a\xc7\xfb\x9a\xe0\x1c\xc6\x95\x63\x19\xfe\x6b\xdd\xb8\x4c\x3c\x56\x37\xdf\x52\x62\x64\xfc\x7f\xa8\x30\xa3\xf6\x58\x7d\xf9\x7c\x5a\x79\xe0\x83\x7a\x23\xb1\xd0\xcc\xd7\xf2\xd5\xb6\xa5\x41\xb6\x22\x69\x1c\x2e\xac\x59\xdb\x65\xb9\x54\xc0\xf3\x67\xaa\x3c\xce\x6e\xa0\x69\x28\x06\x19\x2f\xcb\x4a\xb3\x07\x60\xd9\x16\x4c\xf1\x56\xd8\xac\x79\xc1\x45\xf9\xef\x52\x13\x4d\xf4\x89\x69\x7e\x99\x6c\x4c\xf1\xe6\x35\x96\xa4\x68\xf8\x83\x84\x3c\xb2\x18\x53\x67\xec\x43\xab\x8f\xe5\xeb\x38\x51\x74\x55\x14\x12\x6e\xaa\x56\x61\xfb\x0e\x73\x05\x3e\x89\x46\x63\x83\xb0\x51\x35\x7d\xfc\x96\xcc\xae\x1b\xc3\x7b\xbf\x72\x76\x7c\x36\xb0\xf5\x7a\xcd\x22\x2f\x15\xb9\xc0\x53\x42\xb4\x35\x65\xa3\x35\x81\xdc\xd6\x62\x7c\xe2\x59\x1d\x30\xe2\xf4\xa4\x31\xef\x94\x35\xc4\x63\xdd\x4d\x81\xa0\xc2\xb2\x4e\x51\xf0\x57\x2b\x94\x71\xca\x31\x43\x83\x1c\x9d\x8a\x04\x12\xa1\xaf\x14\xc6\x07\xd4\xb5\x28\x45\xb3\x8d\x51\xa8\x50\x68\x3a\xf4\xd9\xc6\x8d\x5c\x32\x61\x33\x35\x28\x12\x55\x49\xf9\xc0\xb3\x47\x77\xee\x7f\x76\x35\x62\x70\x69\xa8\x23\x17\x68\x6f\x20\x25\x62\x80\x7d\x87\x34\xec\xfd\x13\x36\x47\xef\x1d\x7c\x32\x39\x72\x96\xbc\xe9\x61\xfa\xbb\xd4\xef\x84\x3a\xbc\x58\xaf\xb7\xf7\xc3\x62\x30\x90\xc2\xaa\xf5\x1a\x83\x24\x96\x82\x65\x38\xc1\x9a\xf5\x5c\xa2\x37\xcf\x0b\x55\xb5\xf5\xb9\xd0\xb0\x9b\x63\x62\x0f\x0a\x20\xe5\x79\xfe\x8e\x58\x63\xd2\x9b\xe6\x55\x09\xb8\xa2\x44\x23\xb1\xf3\xa6\xcd\x32\x04\x16\xe1\x8a\x32\x39\xcb\x69\x5d\x52\x63\xbd\xc6\x41\x56\x27\x34\x99\x12\xa6\xac\xb0\x05\x5b\x1a\x28\x55\x29\x32\xc4\x36\x1c\x83\x95\xa3\x5a\xea\xd4\xfd\xd1\x8a\x86\x5c\x12\x14\x8e\xf9\x62\xb7\xa5\x7f\x74\xdd\x37\xb4\x0f\x4a\x17\x55\xed\x6d\x32\xbc\xd1\x8a\x8a\xbd\x73\x83\xa9\x63\x0b\x8a\xf0\xb9\xcb\x21\x1e\x7b\xd2\x6c\xb9\x82\xf7\xa2\x7c\xa4\xc3\xd0\x2b\x88\x76\x31\xf0\x80\x24\x26\x67\x22\xbf\x1a\x4c\x6c\x6a\xc9\x43\x6d\xe3\xee\x46\xc2\x61\x40\x39\xd3\x5c\x61\x2b\x5e\xcf\xff\x7c\x90\xbc\x7c\x9c\x1b\xcc\x5a\xe3\x5e\xb7\x55\xb0\xf1\xfd\xa7\xd7\x17\x34\xac\x9e\x38\x98\x67\x41\x02\xed\x43\x53\x06\xec\xb8\xdf\x05\x92\x5f\x61\xef\x5d\x77\x6d\xbf\x71\x6d\x62\x6b\xa1\x60\x80\x69\x0c\xaf\x7a\x92\x0f\x77\x77\xfe\x82\x2d\xf2\x48\x70\x62\x33\xc1\x9d\x21\xdb\x0b\x2d\x16\xe7\x91\xac\xf6\xae\x6d\x60\x1f\x0f\x1a\x86\xed\xa5\x6e\xd1\xf9\xf1\x09\x37\x0e\xd7\x89\x69\xd2\x11\xff\x12\x88\xd8\xbc\x15\xf9\x9a\xd0\x42\x99\x55\x39\xfc\xf1\xdb\x4f\xef\xaa\x5d\x8d\x65\x52\x6a\x52\xe8\xc3\x6a\xb5\x51\x1a\x19\x55\xef\xb1\x46\xa0\xa4\xb5\xdb\x27\x66\xf8\x8c\x80\xe1\xd4\x0d\x3a\x1b\x8d\xda\xb4\xe6\xaa\xc1\xc6\x6b\xd6\xbe\x49\x09\xbc\x68\x88\x4a\xf7\x45\x23\x6e\x03\x31\x48\xf0\xca\x53\x33\xe1\x3e\x69\x2d\x64\xb2\xc2\xe3\x30\x18\x47\x5b\x70\x30\xbf\xb1\x41\x6a\xa1\x65\xd7\x1e\x23\xf2\x94\x04\x0d\xd1\x88\xab\xf3\x49\x92\x6e\x45\x3e\x30\x62\x3a\x71\xbb\xdb\x71\xf5\x1c\xcc\xab\x23\xdd\x34\x88\xf6\x69\xf0\x80\x33\xbb\x40\x3c\x7d\xb1\x1d\x5b\xba\x06\x8f\xbd\xe0\xbd\x33\x1d\x56\x7e\xf7\x9a\x4a\x98\xe5\xe6\xe8\x68\xb4\xa3\x7a\x2c\x14\xce\x7c\x27\x37\x78\x69\x59\xd2\x70\x33\xe8\x87\x94\x7d\x90\x5a\x95\x83\xf7\x5c\x1f\x75\x4a\x8a\x05\x1b\x3d\xef\xc8\x0b\xb3\xd9\x91\x57\x2b\x25\x01\x25\x79\x1c\xa5\xe6\xc4\xf6\xf5\x28\x71\xcb\xf4\x8a\xe2\x48\x83\x74\x36\x93\x15\xad\x23\xdd\xdb\xf6\x8a\x5d\x62\x8d\x77\x3f\xcf\xa8\x93\x9b\x6f\x7e\x77\xa6\xa6\x68\x6c\x23\xfa\xd9\x8c\x3a\x9e\x7d\x8f\xf6\x6b\xe7\x0c\xc9\xdd\xdb\x16\xd5\xe3\x3d\x67\xb3\x03\x03\xd9\x80\x3b\x45\x21\xa2\xe3\x8a\x07\x4a\xf7\x2c\x7d\xec\x93\xd7\x7f\x03\x76\x1b\x25\x3b\xbd\x0f\x00\x00")
 
 func assetsScriptJsBytes() ([]byte, error) {
 	return bindataRead(
@@ -189,7 +252,7 @@ func assetsScriptJs() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "assets/script.js", size: 582, mode: os.FileMode(511), modTime: time.Unix(1540915425, 0)}
+	info := bindataFileInfo{name: "assets/script.js", size: 4029, mode: os.FileMode(511), modTime: time.Unix(1792209557, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -266,11 +329,14 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
+	"assets/admin.html": assetsAdminHtml,
+	"assets/adopt.html": assetsAdoptHtml,
 	"assets/create.html": assetsCreateHtml,
 	"assets/error.html": assetsErrorHtml,
 	"assets/finish.html": assetsFinishHtml,
 	"assets/home.html": assetsHomeHtml,
 	"assets/login.html": assetsLoginHtml,
+	"assets/roundtrips.html": assetsRoundtripsHtml,
 	"assets/script.js": assetsScriptJs,
 	"assets/style.css": assetsStyleCss,
 }
@@ -316,11 +382,14 @@ type bintree struct {
 }
 var _bintree = &bintree{nil, map[string]*bintree{
 	"assets": &bintree{nil, map[string]*bintree{
+		"admin.html": &bintree{assetsAdminHtml, map[string]*bintree{}},
+		"adopt.html": &bintree{assetsAdoptHtml, map[string]*bintree{}},
 		"create.html": &bintree{assetsCreateHtml, map[string]*bintree{}},
 		"error.html": &bintree{assetsErrorHtml, map[string]*bintree{}},
 		"finish.html": &bintree{assetsFinishHtml, map[string]*bintree{}},
 		"home.html": &bintree{assetsHomeHtml, map[string]*bintree{}},
 		"login.html": &bintree{assetsLoginHtml, map[string]*bintree{}},
+		"roundtrips.html": &bintree{assetsRoundtripsHtml, map[string]*bintree{}},
 		"script.js": &bintree{assetsScriptJs, map[string]*bintree{}},
 		"style.css": &bintree{assetsStyleCss, map[string]*bintree{}},
 	}},
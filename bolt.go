@@ -0,0 +1,450 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"golang.org/x/oauth2"
+)
+
+// BoltDBStore keeps every record in a local BoltDB file. Only one process can have the file open at a time.
+type BoltDBStore struct {
+	DB *bolt.DB
+	// Path of the database file, my.db when empty
+	Path string
+	// Tokens encrypts the stored tokens. They are stored in plain text when it is nil.
+	Tokens *tokenCipher
+}
+
+var boltBuckets = []string{"Tokens", "LoginSessions", "Leases", "RoundTrips", "UserPrefs"}
+
+func (s *BoltDBStore) Open(ctx context.Context) error {
+	path := s.Path
+	if path == "" {
+		path = "my.db"
+	}
+
+	// Without a timeout Open waits forever for another process to let go of the file
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return fmt.Errorf("Opening %s: %v", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range boltBuckets {
+			if _, err := tx.CreateBucketIfNotExists([]byte(bucket)); err != nil {
+				return fmt.Errorf("Create bucket: %s", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return err
+	}
+
+	s.DB = db
+	return nil
+}
+
+func (s *BoltDBStore) Close() error {
+	return s.DB.Close()
+}
+
+// view and update run a BoltDB transaction unless ctx is already done. BoltDB itself cannot be interrupted.
+func (s *BoltDBStore) view(ctx context.Context, fn func(tx *bolt.Tx) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.DB.View(fn)
+}
+
+func (s *BoltDBStore) update(ctx context.Context, fn func(tx *bolt.Tx) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.DB.Update(fn)
+}
+
+func putJSON(b *bolt.Bucket, key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(key), data)
+}
+
+// getJSON reads a record into v, returning an error wrapping ErrNotFound when there is none. kind names the record in the error.
+func getJSON(b *bolt.Bucket, key, kind string, v interface{}) error {
+	data := b.Get([]byte(key))
+	if data == nil {
+		return fmt.Errorf("%s %w: %s", kind, ErrNotFound, key)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// deleteWhere removes the records of a bucket that match. Keys cannot be deleted while iterating over the bucket, so they are collected first.
+func deleteWhere(b *bolt.Bucket, match func(k, v []byte) (bool, error)) error {
+	var keys [][]byte
+	err := b.ForEach(func(k, v []byte) error {
+		ok, err := match(k, v)
+		if ok {
+			keys = append(keys, append([]byte(nil), k...))
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *BoltDBStore) StoreToken(ctx context.Context, userID string, token *oauth2.Token) error {
+	return s.update(ctx, func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte("Tokens"))
+
+		data, err := json.Marshal(token)
+		if err != nil {
+			return err
+		}
+
+		record, err := s.Tokens.Seal(userID, data)
+		if err != nil {
+			return err
+		}
+
+		return b.Put([]byte(userID), record)
+	})
+}
+
+func (s *BoltDBStore) GetToken(ctx context.Context, userID string) (*oauth2.Token, error) {
+	token := &oauth2.Token{}
+	err := s.view(ctx, func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte("Tokens"))
+
+		record := b.Get([]byte(userID))
+		if record == nil {
+			return fmt.Errorf("Token %w: %s", ErrNotFound, userID)
+		}
+
+		data, err := s.Tokens.Open(userID, record)
+		if err != nil {
+			return fmt.Errorf("Token of %s: %v", userID, err)
+		}
+
+		return json.Unmarshal(data, token)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (s *BoltDBStore) DeleteToken(ctx context.Context, userID string) error {
+	return s.update(ctx, func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte("Tokens")).Delete([]byte(userID))
+	})
+}
+
+func (s *BoltDBStore) ReencryptTokens(ctx context.Context) (int, error) {
+	count := 0
+	err := s.update(ctx, func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte("Tokens"))
+
+		// Keys cannot be written while iterating over the bucket
+		records := map[string][]byte{}
+		err := b.ForEach(func(k, v []byte) error {
+			if s.Tokens.Current(v) {
+				return nil
+			}
+
+			userID := string(k)
+			data, err := s.Tokens.Open(userID, v)
+			if err != nil {
+				return fmt.Errorf("Token of %s: %v", userID, err)
+			}
+
+			record, err := s.Tokens.Seal(userID, data)
+			if err != nil {
+				return err
+			}
+
+			records[userID] = record
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for userID, record := range records {
+			if err := b.Put([]byte(userID), record); err != nil {
+				return err
+			}
+		}
+
+		count = len(records)
+		return nil
+	})
+
+	return count, err
+}
+
+func (s *BoltDBStore) ListUsers(ctx context.Context) ([]string, error) {
+	users := []string{}
+	err := s.view(ctx, func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte("Tokens")).ForEach(func(k, v []byte) error {
+			users = append(users, string(k))
+			return nil
+		})
+	})
+
+	return users, err
+}
+
+func (s *BoltDBStore) DeleteUser(ctx context.Context, userID string) error {
+	return s.update(ctx, func(tx *bolt.Tx) error {
+		if err := tx.Bucket([]byte("Tokens")).Delete([]byte(userID)); err != nil {
+			return err
+		}
+		if err := tx.Bucket([]byte("UserPrefs")).Delete([]byte(userID)); err != nil {
+			return err
+		}
+
+		err := deleteWhere(tx.Bucket([]byte("LoginSessions")), func(k, v []byte) (bool, error) {
+			ls := &LoginSession{}
+			err := json.Unmarshal(v, ls)
+			return err == nil && ls.UserID == userID, err
+		})
+		if err != nil {
+			return err
+		}
+
+		return deleteWhere(tx.Bucket([]byte("RoundTrips")), func(k, v []byte) (bool, error) {
+			rt := &RoundTrip{}
+			err := json.Unmarshal(v, rt)
+			return err == nil && rt.UserID == userID, err
+		})
+	})
+}
+
+func (s *BoltDBStore) StoreLoginSession(ctx context.Context, ls *LoginSession) error {
+	return s.update(ctx, func(tx *bolt.Tx) error {
+		return putJSON(tx.Bucket([]byte("LoginSessions")), ls.ID, ls)
+	})
+}
+
+func (s *BoltDBStore) GetLoginSession(ctx context.Context, id string) (*LoginSession, error) {
+	ls := &LoginSession{}
+	err := s.view(ctx, func(tx *bolt.Tx) error {
+		data := tx.Bucket([]byte("LoginSessions")).Get([]byte(id))
+		if data == nil {
+			// The id is a credential so it is left out of the error
+			return fmt.Errorf("Login session %w", ErrNotFound)
+		}
+		return json.Unmarshal(data, ls)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ls, nil
+}
+
+func (s *BoltDBStore) ListUserLoginSessions(ctx context.Context, userID string) ([]*LoginSession, error) {
+	sessions := []*LoginSession{}
+	err := s.view(ctx, func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte("LoginSessions")).ForEach(func(k, v []byte) error {
+			ls := &LoginSession{}
+			if err := json.Unmarshal(v, ls); err != nil {
+				return err
+			}
+			if ls.UserID == userID {
+				sessions = append(sessions, ls)
+			}
+			return nil
+		})
+	})
+
+	return sessions, err
+}
+
+func (s *BoltDBStore) ListLoginSessions(ctx context.Context) ([]*LoginSession, error) {
+	sessions := []*LoginSession{}
+	err := s.view(ctx, func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte("LoginSessions")).ForEach(func(k, v []byte) error {
+			ls := &LoginSession{}
+			if err := json.Unmarshal(v, ls); err != nil {
+				return err
+			}
+			sessions = append(sessions, ls)
+			return nil
+		})
+	})
+
+	return sessions, err
+}
+
+func (s *BoltDBStore) DeleteLoginSession(ctx context.Context, id string) error {
+	return s.update(ctx, func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte("LoginSessions")).Delete([]byte(id))
+	})
+}
+
+func (s *BoltDBStore) DeleteUserLoginSessions(ctx context.Context, userID string) error {
+	return s.update(ctx, func(tx *bolt.Tx) error {
+		return deleteWhere(tx.Bucket([]byte("LoginSessions")), func(k, v []byte) (bool, error) {
+			ls := &LoginSession{}
+			err := json.Unmarshal(v, ls)
+			return err == nil && ls.UserID == userID, err
+		})
+	})
+}
+
+func (s *BoltDBStore) AcquireLease(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	acquired := false
+	err := s.update(ctx, func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte("Leases"))
+
+		if data := b.Get([]byte(name)); data != nil {
+			held := &lease{}
+			if err := json.Unmarshal(data, held); err != nil {
+				return err
+			}
+			if held.Owner != owner && time.Now().Before(held.Expires) {
+				return nil
+			}
+		}
+
+		if err := putJSON(b, name, &lease{Owner: owner, Expires: time.Now().Add(ttl)}); err != nil {
+			return err
+		}
+
+		acquired = true
+		return nil
+	})
+
+	return acquired && err == nil, err
+}
+
+func (s *BoltDBStore) ReleaseLease(ctx context.Context, name, owner string) error {
+	return s.update(ctx, func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte("Leases"))
+
+		data := b.Get([]byte(name))
+		if data == nil {
+			return nil
+		}
+
+		held := &lease{}
+		if err := json.Unmarshal(data, held); err != nil {
+			return err
+		}
+		if held.Owner != owner {
+			return nil
+		}
+
+		return b.Delete([]byte(name))
+	})
+}
+
+func (s *BoltDBStore) SaveRoundTrip(ctx context.Context, rt *RoundTrip) error {
+	return s.update(ctx, func(tx *bolt.Tx) error {
+		return putJSON(tx.Bucket([]byte("RoundTrips")), rt.ID, rt)
+	})
+}
+
+func (s *BoltDBStore) GetRoundTrip(ctx context.Context, id string) (*RoundTrip, error) {
+	rt := &RoundTrip{}
+	err := s.view(ctx, func(tx *bolt.Tx) error {
+		return getJSON(tx.Bucket([]byte("RoundTrips")), id, "Round-trip", rt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rt, nil
+}
+
+func (s *BoltDBStore) ListRoundTrips(ctx context.Context) ([]*RoundTrip, error) {
+	return s.listRoundTrips(ctx, func(rt *RoundTrip) bool { return true })
+}
+
+func (s *BoltDBStore) ListUserRoundTrips(ctx context.Context, userID string) ([]*RoundTrip, error) {
+	return s.listRoundTrips(ctx, func(rt *RoundTrip) bool { return rt.UserID == userID })
+}
+
+func (s *BoltDBStore) listRoundTrips(ctx context.Context, match func(rt *RoundTrip) bool) ([]*RoundTrip, error) {
+	roundTrips := []*RoundTrip{}
+	err := s.view(ctx, func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte("RoundTrips")).ForEach(func(k, v []byte) error {
+			rt := &RoundTrip{}
+			if err := json.Unmarshal(v, rt); err != nil {
+				return err
+			}
+			if match(rt) {
+				roundTrips = append(roundTrips, rt)
+			}
+			return nil
+		})
+	})
+
+	return roundTrips, err
+}
+
+func (s *BoltDBStore) DeleteRoundTrip(ctx context.Context, id string) error {
+	return s.update(ctx, func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte("RoundTrips")).Delete([]byte(id))
+	})
+}
+
+func (s *BoltDBStore) GetUserPrefs(ctx context.Context, userID string) (*UserPrefs, error) {
+	prefs := &UserPrefs{}
+	err := s.view(ctx, func(tx *bolt.Tx) error {
+		data := tx.Bucket([]byte("UserPrefs")).Get([]byte(userID))
+		if data == nil {
+			return nil
+		}
+		return json.Unmarshal(data, prefs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return prefs, nil
+}
+
+func (s *BoltDBStore) SaveUserPrefs(ctx context.Context, userID string, prefs *UserPrefs) error {
+	return s.update(ctx, func(tx *bolt.Tx) error {
+		return putJSON(tx.Bucket([]byte("UserPrefs")), userID, prefs)
+	})
+}
+
+func (s *BoltDBStore) ListUserPrefs(ctx context.Context) (map[string]*UserPrefs, error) {
+	prefs := map[string]*UserPrefs{}
+	err := s.view(ctx, func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte("UserPrefs")).ForEach(func(k, v []byte) error {
+			p := &UserPrefs{}
+			if err := json.Unmarshal(v, p); err != nil {
+				return err
+			}
+			prefs[string(k)] = p
+			return nil
+		})
+	})
+
+	return prefs, err
+}
@@ -29,6 +29,10 @@ echo GOPATH=$GOPATH
 go get github.com/boltdb/bolt/...
 go get -u github.com/jteeuwen/go-bindata/...
 go get -u golang.org/x/oauth2
+go get -u golang.org/x/sync/singleflight
+go get -u github.com/lib/pq
+go get -u github.com/mattn/go-sqlite3
+go get -u github.com/redis/go-redis/v9
 
 DEST=$GOPATH/src/bluebeam
 rm -rf $DEST
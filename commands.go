@@ -0,0 +1,160 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// runCommand runs one of the maintenance commands given on the command line. With BoltDB the server must be stopped first as only one process can open the database.
+func runCommand(ctx context.Context, config *config, tokens *tokenCipher, dataStore DataStore, args []string) error {
+	switch args[0] {
+	case "reencrypt-tokens":
+		// Seals every token with the first of the configured token keys
+		count, err := dataStore.ReencryptTokens(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Re-encrypted %d tokens\n", count)
+		return nil
+	case "migrate":
+		return migrateCommand(ctx, config, tokens, dataStore, args[1:])
+	case "export":
+		return exportCommand(ctx, tokens, dataStore, args[1:])
+	case "import":
+		return importCommand(ctx, tokens, dataStore, args[1:])
+	}
+
+	return fmt.Errorf("Unknown command: %s", args[0])
+}
+
+// migrateCommand copies every record of the configured database to another one: migrate <database> <databaseUrl>
+func migrateCommand(ctx context.Context, source *config, tokens *tokenCipher, dataStore DataStore, args []string) error {
+	// The target is always spelled out, as a default could well be the database being copied
+	if len(args) != 2 || args[1] == "" {
+		return errors.New("Usage: migrate <database> <databaseUrl>")
+	}
+
+	target := &config{Database: args[0], DatabaseURL: args[1]}
+	if sameDatabase(source, target) {
+		return fmt.Errorf("The %s database at %s is the one being migrated", args[0], args[1])
+	}
+
+	// The target seals tokens with the same keys
+	to, err := newDataStore(target, tokens)
+	if err != nil {
+		return err
+	}
+	if err := to.Open(ctx); err != nil {
+		return err
+	}
+	defer to.Close()
+
+	count := 0
+	err = exportRecords(ctx, dataStore, nil, func(rec *backupRecord) error {
+		count++
+		return importRecord(ctx, to, tokens, rec)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Copied %d records to %s\n", count, target.Database)
+	return nil
+}
+
+// sameDatabase reports whether two configurations point at the same database, filling in the default paths and comparing files by their absolute path
+func sameDatabase(a, b *config) bool {
+	backendA, locationA := databaseLocation(a)
+	backendB, locationB := databaseLocation(b)
+	return backendA == backendB && locationA == locationB
+}
+
+// databaseLocation returns the backend of a configuration and where its data is kept
+func databaseLocation(c *config) (string, string) {
+	switch c.Database {
+	case "", "bolt":
+		return "bolt", absPath(c.DatabaseURL, "my.db")
+	case "sqlite":
+		return "sqlite", absPath(c.DatabaseURL, "my.sqlite")
+	}
+	return c.Database, c.DatabaseURL
+}
+
+// absPath is the absolute form of path, or of fallback when path is empty
+func absPath(path, fallback string) string {
+	if path == "" {
+		path = fallback
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// exportCommand writes every record of the configured database to a file: export [-encrypt-tokens] <file>
+func exportCommand(ctx context.Context, tokens *tokenCipher, dataStore DataStore, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	encrypt := flags.Bool("encrypt-tokens", false, "seal the tokens with the current token key")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("Usage: export [-encrypt-tokens] <file>")
+	}
+
+	var seal *tokenCipher
+	if *encrypt {
+		if tokens == nil {
+			return errors.New("Encrypting tokens needs token keys to be configured")
+		}
+		seal = tokens
+	}
+
+	path := flags.Arg(0)
+	// The export holds tokens, so only the owner may read it
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+
+	count, err := writeExport(ctx, file, dataStore, seal)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	fmt.Printf("Exported %d records to %s\n", count, path)
+	return nil
+}
+
+// importCommand writes the records of an export to the configured database, replacing records with the same ids: import <file>
+func importCommand(ctx context.Context, tokens *tokenCipher, dataStore DataStore, args []string) error {
+	if len(args) != 1 {
+		return errors.New("Usage: import <file>")
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	count, err := readImport(ctx, file, dataStore, tokens)
+	if err != nil {
+		return fmt.Errorf("Imported %d records before failing: %v", count, err)
+	}
+
+	fmt.Printf("Imported %d records from %s\n", count, args[0])
+	return nil
+}
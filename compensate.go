@@ -0,0 +1,152 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"bluebeam/gosessionroundtripper/studio"
+)
+
+// Actions that undo a step of a round-trip
+const (
+	compensateDeleteFile    = "DeleteFile"
+	compensateDeleteFolder  = "DeleteFolder"
+	compensateDeleteSession = "DeleteSession"
+	compensateUndoCheckout  = "UndoCheckout"
+	compensateReopenSession = "ReopenSession"
+)
+
+// Compensation undoes a step of a round-trip that completed before a later step failed. Compensations are stored on the RoundTrip as the steps complete so a rollback can still happen after a restart.
+type Compensation struct {
+	Action    string `json:"action"`
+	ProjectID string `json:"projectId,omitempty"`
+	SessionID string `json:"sessionId,omitempty"`
+	FileID    int    `json:"fileId,omitempty"`
+	FolderID  int    `json:"folderId,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Done      bool   `json:"done"`
+	Error     string `json:"error,omitempty"`
+}
+
+func (c *Compensation) String() string {
+	switch c.Action {
+	case compensateDeleteFile:
+		return fmt.Sprintf("Delete uploaded file %s", c.Name)
+	case compensateDeleteFolder:
+		return fmt.Sprintf("Delete folder %s", c.Name)
+	case compensateDeleteSession:
+		return fmt.Sprintf("Delete session %s", c.Name)
+	case compensateUndoCheckout:
+		return fmt.Sprintf("Undo checkout of %s", c.Name)
+	case compensateReopenSession:
+		return fmt.Sprintf("Reopen session %s", c.Name)
+	}
+	return c.Action
+}
+
+// Failed reports whether the compensation was attempted and did not succeed
+func (c *Compensation) Failed() bool {
+	return c.Error != ""
+}
+
+func (c *Compensation) run(ctx context.Context, client *studio.Client) error {
+	switch c.Action {
+	case compensateDeleteFile:
+		return client.Projects.DeleteFile(ctx, c.ProjectID, c.FileID)
+	case compensateDeleteFolder:
+		return client.Projects.DeleteFolder(ctx, c.ProjectID, c.FolderID)
+	case compensateDeleteSession:
+		return client.Sessions.Delete(ctx, c.SessionID)
+	case compensateUndoCheckout:
+		return client.Projects.UndoCheckout(ctx, c.ProjectID, c.FileID)
+	case compensateReopenSession:
+		_, err := client.Sessions.SetStatus(ctx, c.SessionID, "Active")
+		return err
+	}
+	return fmt.Errorf("Unknown compensation: %s", c.Action)
+}
+
+// undone reports whether err says the step was undone already, e.g. by a rollback that stopped before it could checkpoint
+func (c *Compensation) undone(err error) bool {
+	switch c.Action {
+	case compensateDeleteFile, compensateDeleteFolder, compensateDeleteSession:
+		return errors.Is(err, studio.ErrNotFound)
+	case compensateUndoCheckout:
+		return errors.Is(err, studio.ErrNotCheckedOut)
+	}
+	return false
+}
+
+// compensate registers the undo of a step that just completed
+func (rt *RoundTrip) compensate(c *Compensation) {
+	rt.Compensations = append(rt.Compensations, c)
+}
+
+// pendingCompensations reports whether a rollback has anything left to do
+func (rt *RoundTrip) pendingCompensations() bool {
+	for _, c := range rt.Compensations {
+		if !c.Done {
+			return true
+		}
+	}
+	return false
+}
+
+// rollback runs the pending compensations of a round-trip, most recent first. A failed compensation does not stop the others. The outcome of each is recorded through update, which must apply its argument and checkpoint the round-trip. It reports whether everything was undone.
+func rollback(ctx context.Context, client *studio.Client, rt *RoundTrip, update func(fn func())) bool {
+	ok := true
+	for i := len(rt.Compensations) - 1; i >= 0; i-- {
+		c := rt.Compensations[i]
+		if c.Done {
+			continue
+		}
+
+		err := c.run(ctx, client)
+		if err != nil && c.undone(err) {
+			err = nil
+		}
+		if err != nil {
+			fmt.Printf("Rollback %s: %s: %v\n", rt.ID, c, err)
+			ok = false
+		} else {
+			fmt.Printf("Rollback %s: %s\n", rt.ID, c)
+		}
+
+		update(func() {
+			c.Done = err == nil
+			c.Error = ""
+			if err != nil {
+				c.Error = errorMessage(err)
+			}
+		})
+	}
+
+	return ok
+}
+
+// checkpoint is the update for a rollback of a round-trip that is not shared with a running job
+func checkpoint(rt *RoundTrip) func(fn func()) {
+	return func(fn func()) {
+		fn()
+		saveRoundTrip(rt)
+	}
+}
+
+// abortCreate marks a create that failed with err and rolls back everything it did. It returns err so callers can pass it along.
+func abortCreate(client *studio.Client, rt *RoundTrip, err error) error {
+	rt.State = roundTripFailed
+	rt.Error = errorMessage(err)
+	saveRoundTrip(rt)
+
+	// The rollback must run to the end even if the request that failed has gone away
+	if !rollback(context.Background(), client, rt, checkpoint(rt)) {
+		fmt.Printf("Rollback %s: incomplete, retrying at the next startup\n", rt.ID)
+	}
+
+	return err
+}
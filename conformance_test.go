@@ -0,0 +1,501 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// conformanceCheck is one part of the conformance suite. It returns the first way the DataStore misbehaved.
+type conformanceCheck struct {
+	name string
+	run  func(ctx context.Context, store DataStore, prefix string) error
+}
+
+// conformanceChecks is the conformance suite every DataStore backend must pass. The checks only touch records whose ids start with their prefix and delete them again, so they can be run against a database that is in use.
+var conformanceChecks = []conformanceCheck{
+	{"tokens", checkTokens},
+	{"users", checkUsers},
+	{"login sessions", checkLoginSessions},
+	{"leases", checkLeases},
+	{"round-trips", checkRoundTrips},
+	{"user preferences", checkUserPrefs},
+	{"cancelled context", checkCancelledContext},
+}
+
+// testDataStore runs the conformance suite against store, each check as a subtest
+func testDataStore(t *testing.T, store DataStore) {
+	ctx := context.Background()
+	if err := store.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	prefix, err := newLeaseOwner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefix = "conformance-" + prefix[:8] + "-"
+
+	// Clean up after failed checks as well
+	defer func() {
+		for _, userID := range []string{prefix + "a", prefix + "b"} {
+			store.DeleteUser(ctx, userID)
+		}
+	}()
+
+	for _, check := range conformanceChecks {
+		t.Run(check.name, func(t *testing.T) {
+			if err := check.run(ctx, store, prefix); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestBoltDBStore(t *testing.T) {
+	testDataStore(t, &BoltDBStore{Path: filepath.Join(t.TempDir(), "my.db")})
+}
+
+func TestBoltDBStoreEncrypted(t *testing.T) {
+	testDataStore(t, &BoltDBStore{Path: filepath.Join(t.TempDir(), "my.db"), Tokens: testTokenCipher(t)})
+}
+
+func TestSQLiteStore(t *testing.T) {
+	testDataStore(t, &SQLStore{Driver: "sqlite3", Source: filepath.Join(t.TempDir(), "my.sqlite")})
+}
+
+// TestPostgresStore needs a database to run against, given as a URL in POSTGRES_TEST_URL
+func TestPostgresStore(t *testing.T) {
+	source := os.Getenv("POSTGRES_TEST_URL")
+	if source == "" {
+		t.Skip("POSTGRES_TEST_URL is not set")
+	}
+	testDataStore(t, &SQLStore{Driver: "postgres", Source: source, Tokens: testTokenCipher(t)})
+}
+
+// testTokenCipher seals tokens with a random master key
+func testTokenCipher(t *testing.T) *tokenCipher {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := newTokenCipher([]string{"test:" + base64.StdEncoding.EncodeToString(key)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokens
+}
+
+func checkTokens(ctx context.Context, store DataStore, prefix string) error {
+	userID := prefix + "a"
+
+	if _, err := store.GetToken(ctx, userID); !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("GetToken of a missing user returned %v, want ErrNotFound", err)
+	}
+
+	token := &oauth2.Token{AccessToken: "access", RefreshToken: "refresh", TokenType: "bearer", Expiry: time.Now().Add(time.Hour).Round(time.Second)}
+	if err := store.StoreToken(ctx, userID, token); err != nil {
+		return err
+	}
+	if err := expectToken(ctx, store, userID, token); err != nil {
+		return err
+	}
+
+	// Storing again replaces the token, as refreshes do
+	token = &oauth2.Token{AccessToken: "access 2", RefreshToken: "refresh 2", TokenType: "bearer", Expiry: time.Now().Add(2 * time.Hour).Round(time.Second)}
+	if err := store.StoreToken(ctx, userID, token); err != nil {
+		return err
+	}
+	if err := expectToken(ctx, store, userID, token); err != nil {
+		return err
+	}
+
+	if _, err := store.ReencryptTokens(ctx); err != nil {
+		return fmt.Errorf("ReencryptTokens: %v", err)
+	}
+	if err := expectToken(ctx, store, userID, token); err != nil {
+		return fmt.Errorf("After ReencryptTokens: %v", err)
+	}
+
+	if err := store.DeleteToken(ctx, userID); err != nil {
+		return err
+	}
+	if _, err := store.GetToken(ctx, userID); !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("GetToken of a deleted token returned %v, want ErrNotFound", err)
+	}
+	if err := store.DeleteToken(ctx, userID); err != nil {
+		return fmt.Errorf("Deleting a missing token: %v", err)
+	}
+
+	return nil
+}
+
+func expectToken(ctx context.Context, store DataStore, userID string, want *oauth2.Token) error {
+	got, err := store.GetToken(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if got.AccessToken != want.AccessToken || got.RefreshToken != want.RefreshToken || got.TokenType != want.TokenType || !got.Expiry.Equal(want.Expiry) {
+		return fmt.Errorf("GetToken returned %+v, want %+v", got, want)
+	}
+	return nil
+}
+
+func checkUsers(ctx context.Context, store DataStore, prefix string) error {
+	a, b := prefix+"a", prefix+"b"
+
+	for _, userID := range []string{a, b} {
+		if err := store.StoreToken(ctx, userID, &oauth2.Token{AccessToken: userID}); err != nil {
+			return err
+		}
+		if err := store.StoreLoginSession(ctx, &LoginSession{ID: userID + "-session", UserID: userID, Expires: time.Now().Add(time.Hour)}); err != nil {
+			return err
+		}
+		if err := store.SaveUserPrefs(ctx, userID, &UserPrefs{ProjectID: userID}); err != nil {
+			return err
+		}
+		if err := store.SaveRoundTrip(ctx, &RoundTrip{ID: userID + "-rt", UserID: userID}); err != nil {
+			return err
+		}
+	}
+
+	users, err := store.ListUsers(ctx)
+	if err != nil {
+		return err
+	}
+	if !contains(users, a) || !contains(users, b) {
+		return errors.New("ListUsers is missing users with tokens")
+	}
+
+	if err := store.DeleteUser(ctx, a); err != nil {
+		return err
+	}
+
+	users, err = store.ListUsers(ctx)
+	if err != nil {
+		return err
+	}
+	if contains(users, a) || !contains(users, b) {
+		return errors.New("ListUsers does not reflect DeleteUser")
+	}
+
+	// Everything of the deleted user is gone, and nothing of the other
+	if _, err := store.GetLoginSession(ctx, a+"-session"); !errors.Is(err, ErrNotFound) {
+		return errors.New("DeleteUser left a login session behind")
+	}
+	if _, err := store.GetRoundTrip(ctx, a+"-rt"); !errors.Is(err, ErrNotFound) {
+		return errors.New("DeleteUser left a round-trip behind")
+	}
+	if prefs, err := store.GetUserPrefs(ctx, a); err != nil || prefs.ProjectID != "" {
+		return errors.New("DeleteUser left the preferences behind")
+	}
+	if _, err := store.GetLoginSession(ctx, b+"-session"); err != nil {
+		return fmt.Errorf("DeleteUser removed a login session of another user: %v", err)
+	}
+	if _, err := store.GetRoundTrip(ctx, b+"-rt"); err != nil {
+		return fmt.Errorf("DeleteUser removed a round-trip of another user: %v", err)
+	}
+
+	if err := store.DeleteUser(ctx, b); err != nil {
+		return err
+	}
+	// Deleting a user that is already gone is not an error
+	return store.DeleteUser(ctx, b)
+}
+
+func checkLoginSessions(ctx context.Context, store DataStore, prefix string) error {
+	a, b := prefix+"a", prefix+"b"
+	sessions := []*LoginSession{
+		{ID: prefix + "1", UserID: a, Created: time.Now().UTC().Round(time.Second), Expires: time.Now().Add(time.Hour).UTC().Round(time.Second)},
+		{ID: prefix + "2", UserID: a},
+		{ID: prefix + "3", UserID: b},
+	}
+
+	for _, s := range sessions {
+		if err := store.StoreLoginSession(ctx, s); err != nil {
+			return err
+		}
+	}
+
+	got, err := store.GetLoginSession(ctx, sessions[0].ID)
+	if err != nil {
+		return err
+	}
+	if err := sameJSON(got, sessions[0]); err != nil {
+		return fmt.Errorf("GetLoginSession: %v", err)
+	}
+
+	list, err := store.ListUserLoginSessions(ctx, a)
+	if err != nil {
+		return err
+	}
+	if len(list) != 2 {
+		return fmt.Errorf("ListUserLoginSessions returned %d sessions, want 2", len(list))
+	}
+
+	all, err := store.ListLoginSessions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, s := range sessions {
+		if !containsLoginSession(all, s.ID) {
+			return fmt.Errorf("ListLoginSessions is missing the session of %s", s.UserID)
+		}
+	}
+
+	if err := store.DeleteLoginSession(ctx, sessions[0].ID); err != nil {
+		return err
+	}
+	if _, err := store.GetLoginSession(ctx, sessions[0].ID); !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("GetLoginSession of a deleted session returned %v, want ErrNotFound", err)
+	}
+
+	if err := store.DeleteUserLoginSessions(ctx, a); err != nil {
+		return err
+	}
+	if _, err := store.GetLoginSession(ctx, sessions[1].ID); !errors.Is(err, ErrNotFound) {
+		return errors.New("DeleteUserLoginSessions left a session of the user")
+	}
+	if _, err := store.GetLoginSession(ctx, sessions[2].ID); err != nil {
+		return fmt.Errorf("DeleteUserLoginSessions removed a session of another user: %v", err)
+	}
+
+	return store.DeleteLoginSession(ctx, sessions[2].ID)
+}
+
+func checkLeases(ctx context.Context, store DataStore, prefix string) error {
+	name := prefix + "lease"
+
+	expect := func(owner string, want bool) error {
+		got, err := store.AcquireLease(ctx, name, owner, time.Minute)
+		if err != nil {
+			return err
+		}
+		if got != want {
+			return fmt.Errorf("AcquireLease by %s returned %v, want %v", owner, got, want)
+		}
+		return nil
+	}
+
+	if err := expect("a", true); err != nil {
+		return err
+	}
+	if err := expect("b", false); err != nil {
+		return err
+	}
+	// The holder can renew its lease
+	if err := expect("a", true); err != nil {
+		return err
+	}
+
+	// Only the holder can release a lease
+	if err := store.ReleaseLease(ctx, name, "b"); err != nil {
+		return err
+	}
+	if err := expect("b", false); err != nil {
+		return err
+	}
+	if err := store.ReleaseLease(ctx, name, "a"); err != nil {
+		return err
+	}
+	if err := expect("b", true); err != nil {
+		return err
+	}
+	if err := store.ReleaseLease(ctx, name, "b"); err != nil {
+		return err
+	}
+
+	// An expired lease is free to take
+	if _, err := store.AcquireLease(ctx, name, "a", 10*time.Millisecond); err != nil {
+		return err
+	}
+	time.Sleep(50 * time.Millisecond)
+	if err := expect("b", true); err != nil {
+		return fmt.Errorf("After expiry: %v", err)
+	}
+
+	return store.ReleaseLease(ctx, name, "b")
+}
+
+func checkRoundTrips(ctx context.Context, store DataStore, prefix string) error {
+	a, b := prefix+"a", prefix+"b"
+
+	if _, err := store.GetRoundTrip(ctx, prefix+"missing"); !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("GetRoundTrip of a missing round-trip returned %v, want ErrNotFound", err)
+	}
+
+	now := time.Now().UTC().Round(time.Second)
+	rt := &RoundTrip{
+		ID:          prefix + "1",
+		UserID:      a,
+		SessionName: "Conformance",
+		SessionID:   "123-456-789",
+		ProjectID:   "project",
+		State:       roundTripActive,
+		Step:        stepCheckedOut,
+		Files: []*RoundTripFile{
+			{Name: "a.pdf", FileProjectID: 1, FileSessionID: 2, Step: fileCheckedOut},
+		},
+		Compensations: []*Compensation{
+			{Action: compensateDeleteSession, SessionID: "123-456-789", Name: "Conformance"},
+		},
+		Created: now,
+		Updated: now,
+	}
+	other := &RoundTrip{ID: prefix + "2", UserID: b, State: roundTripComplete, Created: now, Updated: now}
+
+	for _, r := range []*RoundTrip{rt, other} {
+		if err := store.SaveRoundTrip(ctx, r); err != nil {
+			return err
+		}
+	}
+
+	got, err := store.GetRoundTrip(ctx, rt.ID)
+	if err != nil {
+		return err
+	}
+	if err := sameJSON(got, rt); err != nil {
+		return fmt.Errorf("GetRoundTrip: %v", err)
+	}
+
+	// Saving again replaces the record, as checkpoints do
+	rt.Step = stepFinalizing
+	rt.Files[0].Step = fileSnapshotStarted
+	if err := store.SaveRoundTrip(ctx, rt); err != nil {
+		return err
+	}
+	got, err = store.GetRoundTrip(ctx, rt.ID)
+	if err != nil {
+		return err
+	}
+	if err := sameJSON(got, rt); err != nil {
+		return fmt.Errorf("GetRoundTrip after an update: %v", err)
+	}
+
+	all, err := store.ListRoundTrips(ctx)
+	if err != nil {
+		return err
+	}
+	if !containsRoundTrip(all, rt.ID) || !containsRoundTrip(all, other.ID) {
+		return errors.New("ListRoundTrips is missing round-trips")
+	}
+
+	mine, err := store.ListUserRoundTrips(ctx, a)
+	if err != nil {
+		return err
+	}
+	if len(mine) != 1 || mine[0].ID != rt.ID {
+		return fmt.Errorf("ListUserRoundTrips returned %d round-trips, want only %s", len(mine), rt.ID)
+	}
+
+	for _, r := range []*RoundTrip{rt, other} {
+		if err := store.DeleteRoundTrip(ctx, r.ID); err != nil {
+			return err
+		}
+	}
+	if _, err := store.GetRoundTrip(ctx, rt.ID); !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("GetRoundTrip of a deleted round-trip returned %v, want ErrNotFound", err)
+	}
+
+	return nil
+}
+
+func checkUserPrefs(ctx context.Context, store DataStore, prefix string) error {
+	userID := prefix + "a"
+
+	prefs, err := store.GetUserPrefs(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if prefs == nil || *prefs != (UserPrefs{}) {
+		return fmt.Errorf("GetUserPrefs of a user without preferences returned %+v, want empty preferences", prefs)
+	}
+
+	want := &UserPrefs{ProjectID: "project", FolderID: 7, DatedFolder: true, Source: "existing", Updated: time.Now().UTC().Round(time.Second)}
+	if err := store.SaveUserPrefs(ctx, userID, want); err != nil {
+		return err
+	}
+
+	prefs, err = store.GetUserPrefs(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := sameJSON(prefs, want); err != nil {
+		return fmt.Errorf("GetUserPrefs: %v", err)
+	}
+
+	// Preferences are listed whether or not the user has a token
+	all, err := store.ListUserPrefs(ctx)
+	if err != nil {
+		return err
+	}
+	if err := sameJSON(all[userID], want); err != nil {
+		return fmt.Errorf("ListUserPrefs: %v", err)
+	}
+
+	return store.DeleteUser(ctx, userID)
+}
+
+func checkCancelledContext(ctx context.Context, store DataStore, prefix string) error {
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	if err := store.StoreToken(cancelled, prefix+"a", &oauth2.Token{AccessToken: "access"}); err == nil {
+		store.DeleteToken(ctx, prefix+"a")
+		return errors.New("StoreToken succeeded with a cancelled context")
+	}
+	if _, err := store.GetRoundTrip(cancelled, prefix+"missing"); err == nil || errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("GetRoundTrip with a cancelled context returned %v, want the context error", err)
+	}
+
+	return nil
+}
+
+func contains(list []string, item string) bool {
+	for _, i := range list {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
+
+func containsLoginSession(list []*LoginSession, id string) bool {
+	for _, ls := range list {
+		if ls.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// sameJSON compares records by their JSON, which is how every backend stores them
+func sameJSON(got, want interface{}) error {
+	g, err := json.Marshal(got)
+	if err != nil {
+		return err
+	}
+	w, err := json.Marshal(want)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(g, w) {
+		return fmt.Errorf("got %s, want %s", g, w)
+	}
+	return nil
+}
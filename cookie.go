@@ -0,0 +1,215 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var errInvalidCookie = errors.New("Invalid cookie")
+
+// cookieCodec protects the values of the cookies it sets. A value is encrypted with AES-GCM and the result is signed with HMAC-SHA256, so cookies can be neither read nor forged by the browser.
+// New cookies are always sealed with the first key. Every key is accepted when reading, so a key can be rotated out by adding a new one in front of it and removing it once its cookies have expired.
+type cookieCodec struct {
+	keys   []*cookieKey
+	secure bool
+}
+
+type cookieKey struct {
+	id      []byte
+	aead    cipher.AEAD
+	hashKey []byte
+}
+
+const (
+	cookieKeyIDSize     = 4
+	cookieTimestampSize = 8
+)
+
+// newCookieCodec derives the encryption and signing keys from each secret. secure marks the cookies as HTTPS only.
+func newCookieCodec(secrets []string, secure bool) (*cookieCodec, error) {
+	codec := &cookieCodec{secure: secure}
+
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+
+		// Separate keys are used for encrypting and for signing
+		encKey := deriveKey(secret, "cookie encryption")
+		hashKey := deriveKey(secret, "cookie signing")
+
+		block, err := aes.NewCipher(encKey)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+
+		id := sha256.Sum256(hashKey)
+		codec.keys = append(codec.keys, &cookieKey{id: id[:cookieKeyIDSize], aead: aead, hashKey: hashKey})
+	}
+
+	if len(codec.keys) == 0 {
+		return nil, errors.New("A cookie key or client secret is required to protect the session cookie")
+	}
+
+	return codec, nil
+}
+
+func deriveKey(secret, purpose string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+// Encode seals value for the named cookie. The layout is key id, timestamp, nonce, ciphertext and then the signature of all of it.
+func (c *cookieCodec) Encode(name, value string) (string, error) {
+	key := c.keys[0]
+
+	header := make([]byte, cookieKeyIDSize+cookieTimestampSize, cookieKeyIDSize+cookieTimestampSize+key.aead.NonceSize())
+	copy(header, key.id)
+	binary.BigEndian.PutUint64(header[cookieKeyIDSize:], uint64(time.Now().Unix()))
+
+	nonce := make([]byte, key.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	// The cookie name and the header are authenticated so a value cannot be moved to another cookie or have its timestamp changed
+	sealed := key.aead.Seal(append(header, nonce...), nonce, []byte(value), additionalData(name, header))
+	sealed = append(sealed, key.sign(name, sealed)...)
+
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Decode checks the signature and decrypts a value sealed by Encode. Values older than maxAge are rejected.
+func (c *cookieCodec) Decode(name, encoded string, maxAge time.Duration) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", errInvalidCookie
+	}
+
+	if len(data) < cookieKeyIDSize+cookieTimestampSize+sha256.Size {
+		return "", errInvalidCookie
+	}
+
+	key := c.key(data[:cookieKeyIDSize])
+	if key == nil {
+		return "", errInvalidCookie
+	}
+
+	signed, signature := data[:len(data)-sha256.Size], data[len(data)-sha256.Size:]
+	if !hmac.Equal(signature, key.sign(name, signed)) {
+		return "", errInvalidCookie
+	}
+
+	header := signed[:cookieKeyIDSize+cookieTimestampSize]
+	issued := time.Unix(int64(binary.BigEndian.Uint64(header[cookieKeyIDSize:])), 0)
+	if maxAge > 0 && time.Since(issued) > maxAge {
+		return "", errInvalidCookie
+	}
+
+	rest := signed[len(header):]
+	if len(rest) < key.aead.NonceSize() {
+		return "", errInvalidCookie
+	}
+	nonce, ciphertext := rest[:key.aead.NonceSize()], rest[key.aead.NonceSize():]
+
+	value, err := key.aead.Open(nil, nonce, ciphertext, additionalData(name, header))
+	if err != nil {
+		return "", errInvalidCookie
+	}
+
+	return string(value), nil
+}
+
+func (c *cookieCodec) key(id []byte) *cookieKey {
+	for _, k := range c.keys {
+		if hmac.Equal(k.id, id) {
+			return k
+		}
+	}
+	return nil
+}
+
+func (k *cookieKey) sign(name string, data []byte) []byte {
+	mac := hmac.New(sha256.New, k.hashKey)
+	mac.Write([]byte(name))
+	mac.Write([]byte{0})
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+func additionalData(name string, header []byte) []byte {
+	return append([]byte(name+"\x00"), header...)
+}
+
+// SetCookie sets a protected cookie that the browser keeps for maxAge
+func (c *cookieCodec) SetCookie(w http.ResponseWriter, name, value string, maxAge time.Duration) error {
+	encoded, err := c.Encode(name, value)
+	if err != nil {
+		return err
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    encoded,
+		Path:     "/",
+		Expires:  time.Now().Add(maxAge),
+		MaxAge:   int(maxAge.Seconds()),
+		Secure:   c.secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return nil
+}
+
+// Value reads a protected cookie set by SetCookie with the same maxAge
+func (c *cookieCodec) Value(r *http.Request, name string, maxAge time.Duration) (string, error) {
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+
+	return c.Decode(name, cookie.Value, maxAge)
+}
+
+// ClearCookie removes a cookie from the browser
+func (c *cookieCodec) ClearCookie(w http.ResponseWriter, name string) {
+	http.SetCookie(w, &http.Cookie{Name: name, Value: "", Path: "/", MaxAge: -1, Secure: c.secure, HttpOnly: true})
+}
+
+// cookieSecrets returns the configured cookie keys, newest first, falling back to the client secret
+func cookieSecrets(config *config) []string {
+	if len(config.CookieKeys) > 0 {
+		return config.CookieKeys
+	}
+	return []string{config.ClientSecret}
+}
+
+// splitList splits a comma separated environment variable
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
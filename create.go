@@ -6,56 +6,91 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"html/template"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"bluebeam/gosessionroundtripper/studio"
 )
 
 func createPage(w http.ResponseWriter, r *http.Request) {
-	client := getOAuthClient(r.Context())
-
-	projectID := r.FormValue("project")
-	sessionName := r.FormValue("session")
-
-	r.ParseMultipartForm(32 << 20)
+	ctx := r.Context()
+	u := ctx.Value("user").(user)
 
-	file, handler, err := r.FormFile("sessionFile")
+	client, err := getStudioClient(ctx)
 	if err != nil {
 		redirectToError(w, r, err)
 		return
 	}
 
-	defer file.Close()
+	// A form that cannot be read would otherwise look like one without any files
+	if err := r.ParseMultipartForm(32 << 20); err != nil && err != http.ErrNotMultipart {
+		redirectToError(w, r, fmt.Errorf("The uploaded files could not be read: %v", err))
+		return
+	}
+
+	projectID := r.FormValue("project")
+	sessionName := r.FormValue("session")
+	folderID, _ := strconv.Atoi(r.FormValue("folder"))
 
-	projectFilesResponse, err := startFileUpload(client, projectID, handler.Filename)
+	rt, err := newRoundTrip(u.UserID, sessionName, projectID)
 	if err != nil {
 		redirectToError(w, r, err)
 		return
 	}
-
-	err = uploadToAWS(projectFilesResponse, file, handler.Size)
+	// Held until the create and any rollback are done, so an instance starting meanwhile does not resume it
+	lease, err := leaseRoundTrip(rt.ID)
 	if err != nil {
 		redirectToError(w, r, err)
 		return
 	}
+	defer lease.Release()
 
-	err = confirmUpload(client, projectID, projectFilesResponse.ID)
+	// The files to check out are either picked from the project or uploaded into it
+	if r.FormValue("source") == "existing" {
+		err = addProjectFiles(ctx, client, r, rt)
+	} else {
+		err = uploadProjectFiles(ctx, client, r, rt, folderID)
+	}
 	if err != nil {
-		redirectToError(w, r, err)
+		abortCreate(client, rt, err)
+		renderError(w, r, err, rt.Compensations)
 		return
 	}
 
-	sessionResponse, err := createSession(client, sessionName)
-	if err != nil {
-		redirectToError(w, r, err)
+	rt.Step = stepFilesReady
+	if err := saveRoundTrip(rt); err != nil {
+		abortCreate(client, rt, err)
+		renderError(w, r, err, rt.Compensations)
 		return
 	}
 
-	checkoutResponse, err := checkoutFileToSession(client, sessionResponse.ID, projectID, projectFilesResponse.ID)
-	if err != nil {
-		redirectToError(w, r, err)
+	if err := runCreate(ctx, client, rt); err != nil {
+		renderError(w, r, err, rt.Compensations)
 		return
 	}
 
+	// Remember the choices as the defaults for the next round-trip
+	prefs := &UserPrefs{
+		ProjectID:   projectID,
+		FolderID:    folderID,
+		DatedFolder: r.FormValue("datedFolder") != "",
+		Source:      r.FormValue("source"),
+		Updated:     time.Now().UTC(),
+	}
+	if err := env.DataStore.SaveUserPrefs(ctx, u.UserID, prefs); err != nil {
+		fmt.Printf("Saving preferences of %s: %v\n", u.UserID, err)
+	}
+
 	html, err := Asset("assets/create.html")
 	if err != nil {
 		redirectToError(w, r, err)
@@ -64,13 +99,229 @@ func createPage(w http.ResponseWriter, r *http.Request) {
 
 	t, _ := template.New("createSession").Parse(string(html))
 
-	createSessionData := struct {
-		SessionName   string
-		SessionID     string
-		ProjectID     string
-		FileSessionID int
-		FileProjectID int
-	}{SessionName: sessionName, SessionID: sessionResponse.ID, ProjectID: projectID, FileSessionID: checkoutResponse.ID, FileProjectID: projectFilesResponse.ID}
+	createData := struct {
+		*RoundTrip
+		CSRFToken string
+	}{rt, csrfToken(r)}
+
+	t.Execute(w, createData)
+}
+
+// runCreate creates the Session and checks the round-trip files out to it. It picks up from the last checkpoint so it can also finish a create interrupted by a restart. On failure everything the create did is rolled back.
+func runCreate(ctx context.Context, client *studio.Client, rt *RoundTrip) error {
+	if rt.Step < stepSessionCreated {
+		sessionRequest := newSessionRequest(rt.SessionName)
+		sessionResponse, err := client.Sessions.Create(ctx, sessionRequest)
+		if err != nil {
+			return abortCreate(client, rt, err)
+		}
+
+		rt.SessionID = sessionResponse.ID
+		rt.SessionEndDate = sessionRequest.SessionEndDate
+		rt.Step = stepSessionCreated
+		rt.compensate(&Compensation{Action: compensateDeleteSession, SessionID: rt.SessionID, Name: rt.SessionName})
+		if err := saveRoundTrip(rt); err != nil {
+			return abortCreate(client, rt, err)
+		}
+	}
+
+	for _, f := range rt.Files {
+		if f.Step >= fileCheckedOut {
+			continue
+		}
+
+		checkoutResponse, err := client.Projects.CheckoutToSession(ctx, rt.ProjectID, f.FileProjectID, rt.SessionID)
+		if err != nil {
+			f.Error = errorMessage(err)
+			return abortCreate(client, rt, &fileError{f.Name, err})
+		}
+
+		f.FileSessionID = checkoutResponse.ID
+		f.Step = fileCheckedOut
+		rt.compensate(&Compensation{Action: compensateUndoCheckout, ProjectID: rt.ProjectID, FileID: f.FileProjectID, Name: f.Name})
+		if err := saveRoundTrip(rt); err != nil {
+			return abortCreate(client, rt, err)
+		}
+	}
+
+	// The round-trip now stands on its own; a failed finish must not delete what was created here
+	rt.Compensations = nil
+	rt.Step = stepCheckedOut
+	rt.State = roundTripActive
+	return saveRoundTrip(rt)
+}
+
+// addProjectFiles adds the files picked from the project on the form to the round-trip
+func addProjectFiles(ctx context.Context, client *studio.Client, r *http.Request, rt *RoundTrip) error {
+	for _, v := range r.Form["projectFile"] {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("Invalid project file: %s", v)
+		}
+
+		projectFile, err := client.Projects.GetFile(ctx, rt.ProjectID, id)
+		if err != nil {
+			return err
+		}
+
+		rt.Files = append(rt.Files, &RoundTripFile{Name: projectFile.Name, FileProjectID: projectFile.ID, Step: fileUploaded})
+	}
+
+	if len(rt.Files) == 0 {
+		return errors.New("No project files were selected")
+	}
+
+	return saveRoundTrip(rt)
+}
+
+// uploadProjectFiles uploads every sessionFile from the form into the project and adds them to the round-trip
+func uploadProjectFiles(ctx context.Context, client *studio.Client, r *http.Request, rt *RoundTrip, folderID int) error {
+	if r.MultipartForm == nil || len(r.MultipartForm.File["sessionFile"]) == 0 {
+		return errors.New("No files were uploaded")
+	}
+
+	// Optionally keep each round-trip in its own folder
+	if r.FormValue("datedFolder") != "" {
+		folderName := datedFolderName(rt.SessionName, time.Now())
+		folderResponse, err := client.Projects.CreateFolder(ctx, rt.ProjectID, &studio.CreateProjectFolder{
+			Name:           folderName,
+			ParentFolderID: folderID,
+			Comment:        "Created by Roundtripper",
+		})
+		if err != nil {
+			return err
+		}
+		folderID = folderResponse.ID
+		rt.compensate(&Compensation{Action: compensateDeleteFolder, ProjectID: rt.ProjectID, FolderID: folderID, Name: folderName})
+	}
+
+	handlers := r.MultipartForm.File["sessionFile"]
+	for _, handler := range handlers {
+		rt.Files = append(rt.Files, &RoundTripFile{Name: handler.Filename})
+	}
+	if err := saveRoundTrip(rt); err != nil {
+		return err
+	}
+
+	for i, handler := range handlers {
+		f := rt.Files[i]
+		if err := uploadProjectFile(ctx, client, rt, f, folderID, handler); err != nil {
+			f.Error = errorMessage(err)
+			return &fileError{handler.Filename, err}
+		}
+	}
+
+	return nil
+}
+
+// uploadProjectFile uploads a single file from the form into a project folder as file f of the round-trip
+func uploadProjectFile(ctx context.Context, client *studio.Client, rt *RoundTrip, f *RoundTripFile, folderID int, handler *multipart.FileHeader) error {
+	file, err := handler.Open()
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	// Studio is told the size and CRC up front and the storage needs the MD5 before the contents, so the file is buffered once, working them out on the way, and sent from the buffer
+	buffer, digest, err := bufferUpload(file)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(buffer.Name())
+	defer buffer.Close()
+
+	projectFilesResponse, err := client.Projects.StartFileUpload(ctx, rt.ProjectID, &studio.ProjectFilesRequest{
+		Name:           handler.Filename,
+		ParentFolderID: folderID,
+		Size:           int(digest.Size),
+		CRC:            digest.CRC,
+	})
+	if err != nil {
+		return err
+	}
+
+	// The project file exists from here on, even before its contents are uploaded
+	f.FileProjectID = projectFilesResponse.ID
+	rt.compensate(&Compensation{Action: compensateDeleteFile, ProjectID: rt.ProjectID, FileID: f.FileProjectID, Name: f.Name})
+	if err := saveRoundTrip(rt); err != nil {
+		return err
+	}
+
+	err = client.Upload(ctx, projectFilesResponse.UploadUrl, projectFilesResponse.UploadContentType, buffer, digest)
+	if err != nil {
+		return err
+	}
 
-	t.Execute(w, createSessionData)
+	err = client.Projects.ConfirmUpload(ctx, rt.ProjectID, f.FileProjectID)
+	if err != nil {
+		return err
+	}
+
+	f.Step = fileUploaded
+	return saveRoundTrip(rt)
+}
+
+// bufferUpload copies an uploaded file to a temporary file in a single pass that also works out its digest. The temporary file is returned at its start, ready to be sent, and is left for the caller to remove.
+func bufferUpload(file io.Reader) (*os.File, *studio.Digest, error) {
+	buffer, err := ioutil.TempFile("", "roundtripper-upload-")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	digester := studio.NewDigester()
+	_, err = io.Copy(buffer, io.TeeReader(file, digester))
+	if err == nil {
+		_, err = buffer.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		buffer.Close()
+		os.Remove(buffer.Name())
+		return nil, nil, err
+	}
+
+	return buffer, digester.Digest(), nil
+}
+
+// datedFolderName names the folder created for a single round-trip
+func datedFolderName(sessionName string, t time.Time) string {
+	return t.Format("2006-01-02 1504") + " " + strings.Map(func(r rune) rune {
+		// Studio rejects folder names containing path characters
+		if strings.ContainsRune(`\/:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, sessionName)
+}
+
+// newSessionRequest describes the Sessions created by the app: open to anyone with the link for 4 weeks
+func newSessionRequest(sessionName string) *studio.CreateSession {
+	return &studio.CreateSession{
+		Name:           sessionName,
+		Notification:   true,
+		Restricted:     false,
+		SessionEndDate: time.Now().Add(time.Hour * 24 * 7 * time.Duration(4)),
+		DefaultPermissions: []studio.DefaultSessionPermissions{
+			studio.DefaultSessionPermissions{
+				Type:  "SaveCopy",
+				Allow: "Allow",
+			},
+			studio.DefaultSessionPermissions{
+				Type:  "PrintCopy",
+				Allow: "Allow",
+			},
+			studio.DefaultSessionPermissions{
+				Type:  "Markup",
+				Allow: "Allow",
+			},
+			studio.DefaultSessionPermissions{
+				Type:  "MarkupAlert",
+				Allow: "Allow",
+			},
+			studio.DefaultSessionPermissions{
+				Type:  "AddDocuments",
+				Allow: "Allow",
+			},
+		},
+	}
 }
@@ -0,0 +1,73 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+)
+
+// csrfField is the name of the form field, and csrfHeader the request header, that carry the CSRF token
+const (
+	csrfField  = "csrfToken"
+	csrfHeader = "X-CSRF-Token"
+)
+
+// csrfHandler rejects state changing requests that do not carry the CSRF token of the user's login session, so other sites cannot post forms on the user's behalf.
+// It must be wrapped by authHandler. Pages put the token from csrfToken into their forms.
+func csrfHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value("user").(user)
+
+		switch r.Method {
+		case "GET", "HEAD", "OPTIONS":
+		default:
+			token := r.Header.Get(csrfHeader)
+			if token == "" {
+				// Multipart forms are parsed with the same limit createPage uses
+				r.ParseMultipartForm(32 << 20)
+				token = r.PostFormValue(csrfField)
+			}
+
+			if !env.Cookies.VerifyCSRFToken(u.SessionID, token) {
+				fmt.Printf("Rejected %s %s from %s: missing or invalid CSRF token\n", r.Method, r.URL.Path, u.UserID)
+				http.Error(w, "Invalid or missing CSRF token. Reload the page and try again.", http.StatusForbidden)
+				return
+			}
+		}
+
+		ctx := context.WithValue(r.Context(), "csrfToken", env.Cookies.CSRFToken(u.SessionID))
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// csrfToken returns the token to embed in the forms of a page served through csrfHandler
+func csrfToken(r *http.Request) string {
+	token, _ := r.Context().Value("csrfToken").(string)
+	return token
+}
+
+// CSRFToken derives the CSRF token of a login session. Being tied to the session id, it cannot be guessed without the session cookie and needs no storage of its own.
+func (c *cookieCodec) CSRFToken(sessionID string) string {
+	return base64.RawURLEncoding.EncodeToString(c.keys[0].sign(csrfField, []byte(sessionID)))
+}
+
+// VerifyCSRFToken checks a token against every cookie key so pages rendered before a key rotation still post
+func (c *cookieCodec) VerifyCSRFToken(sessionID, token string) bool {
+	mac, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || sessionID == "" {
+		return false
+	}
+
+	for _, k := range c.keys {
+		if hmac.Equal(mac, k.sign(csrfField, []byte(sessionID))) {
+			return true
+		}
+	}
+	return false
+}
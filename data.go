@@ -5,73 +5,100 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"time"
 
-	"github.com/boltdb/bolt"
 	"golang.org/x/oauth2"
 )
 
+// DataStore is the persistent storage of the app. Every backend must pass the conformance suite in conformance_test.go.
+// Methods that look up a single record return an error wrapping ErrNotFound when it does not exist. Deleting a record that does not exist is not an error.
 type DataStore interface {
-	New()
-	Close()
-	StoreToken(userID string, token *oauth2.Token) error
-	GetToken(userID string) (*oauth2.Token, error)
+	// Open connects to the backend, creating whatever it needs to store the records
+	Open(ctx context.Context) error
+	Close() error
+
+	// Tokens are the OAuth tokens of the users, one per user
+	StoreToken(ctx context.Context, userID string, token *oauth2.Token) error
+	GetToken(ctx context.Context, userID string) (*oauth2.Token, error)
+	DeleteToken(ctx context.Context, userID string) error
+	// ReencryptTokens seals every token that is not sealed with the current master key again, returning how many were rewritten
+	ReencryptTokens(ctx context.Context) (int, error)
+
+	// ListUsers returns the ids of the users with a stored token
+	ListUsers(ctx context.Context) ([]string, error)
+	// DeleteUser forgets everything stored for a user: their token, login sessions, preferences and round-trips
+	DeleteUser(ctx context.Context, userID string) error
+
+	StoreLoginSession(ctx context.Context, s *LoginSession) error
+	GetLoginSession(ctx context.Context, id string) (*LoginSession, error)
+	ListUserLoginSessions(ctx context.Context, userID string) ([]*LoginSession, error)
+	// ListLoginSessions returns the login sessions of every user
+	ListLoginSessions(ctx context.Context) ([]*LoginSession, error)
+	DeleteLoginSession(ctx context.Context, id string) error
+	DeleteUserLoginSessions(ctx context.Context, userID string) error
+
+	// AcquireLease takes the named lease for owner unless someone else holds it. A lease that has expired is free to take, so a holder that died does not block others for longer than ttl.
+	AcquireLease(ctx context.Context, name, owner string, ttl time.Duration) (bool, error)
+	// ReleaseLease gives up a lease, if owner still holds it
+	ReleaseLease(ctx context.Context, name, owner string) error
+
+	// Round-trips are the records of the create and finish workflows
+	SaveRoundTrip(ctx context.Context, rt *RoundTrip) error
+	GetRoundTrip(ctx context.Context, id string) (*RoundTrip, error)
+	ListRoundTrips(ctx context.Context) ([]*RoundTrip, error)
+	ListUserRoundTrips(ctx context.Context, userID string) ([]*RoundTrip, error)
+	DeleteRoundTrip(ctx context.Context, id string) error
+
+	// GetUserPrefs returns empty preferences for a user who has none saved
+	GetUserPrefs(ctx context.Context, userID string) (*UserPrefs, error)
+	SaveUserPrefs(ctx context.Context, userID string, prefs *UserPrefs) error
+	// ListUserPrefs returns the saved preferences of every user, by user id
+	ListUserPrefs(ctx context.Context) (map[string]*UserPrefs, error)
 }
 
-type BoltDBStore struct {
-	DB *bolt.DB
-}
-
-func (s *BoltDBStore) New() {
-	db, err := bolt.Open("my.db", 0600, nil)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	err = db.Update(func(tx *bolt.Tx) error {
-		_, err := tx.CreateBucketIfNotExists([]byte("Tokens"))
-		if err != nil {
-			return fmt.Errorf("Create bucket: %s", err)
+// newDataStore creates the DataStore chosen by the config. It still needs to be opened.
+func newDataStore(config *config, tokens *tokenCipher) (DataStore, error) {
+	switch config.Database {
+	case "", "bolt":
+		return &BoltDBStore{Path: config.DatabaseURL, Tokens: tokens}, nil
+	case "postgres":
+		if config.DatabaseURL == "" {
+			return nil, errors.New("The postgres database needs a databaseUrl")
 		}
-
-		return nil
-	})
-
-	s.DB = db
-}
-
-func (s *BoltDBStore) Close() {
-	s.DB.Close()
-}
-
-func (s *BoltDBStore) StoreToken(userID string, token *oauth2.Token) error {
-	return s.DB.Update(func(tx *bolt.Tx) error {
-		b := tx.Bucket([]byte("Tokens"))
-
-		data, err := json.Marshal(token)
-		if err != nil {
-			return err
+		return &SQLStore{Driver: "postgres", Source: config.DatabaseURL, Tokens: tokens}, nil
+	case "sqlite":
+		path := config.DatabaseURL
+		if path == "" {
+			path = "my.sqlite"
+		}
+		return &SQLStore{Driver: "sqlite3", Source: path, Tokens: tokens}, nil
+	case "redis":
+		if config.DatabaseURL == "" {
+			return nil, errors.New("The redis database needs a databaseUrl")
 		}
+		return &RedisStore{URL: config.DatabaseURL, Tokens: tokens}, nil
+	}
 
-		err = b.Put([]byte(userID), data)
-		return err
-	})
+	return nil, fmt.Errorf("Unknown database: %s", config.Database)
 }
 
-func (s *BoltDBStore) GetToken(userID string) (*oauth2.Token, error) {
-	token := &oauth2.Token{}
-	err := s.DB.View(func(tx *bolt.Tx) error {
-		b := tx.Bucket([]byte("Tokens"))
-
-		data := b.Get([]byte(userID))
-		if data == nil {
-			return fmt.Errorf("Token Not Found: %s", userID)
-		}
+// ErrNotFound is wrapped by the errors of DataStore lookups for records that do not exist
+var ErrNotFound = errors.New("Not Found")
 
-		return json.Unmarshal(data, token)
-	})
+// UserPrefs are the choices a user made on their last round-trip, used as the defaults of the home page
+type UserPrefs struct {
+	ProjectID   string    `json:"projectId,omitempty"`
+	FolderID    int       `json:"folderId,omitempty"`
+	DatedFolder bool      `json:"datedFolder,omitempty"`
+	Source      string    `json:"source,omitempty"`
+	Updated     time.Time `json:"updated"`
+}
 
-	return token, err
+// lease is held by one owner until it is released or expires
+type lease struct {
+	Owner   string    `json:"owner"`
+	Expires time.Time `json:"expires"`
 }
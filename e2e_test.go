@@ -0,0 +1,978 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"bluebeam/gosessionroundtripper/studio"
+	"bluebeam/gosessionroundtripper/studio/studiotest"
+
+	"golang.org/x/oauth2"
+)
+
+// e2eCheck is one scenario of the end-to-end suite. It returns the first way the round-trip went wrong.
+type e2eCheck struct {
+	name string
+	run  func(ctx context.Context, h *e2eHarness) error
+}
+
+// e2eChecks drive whole round-trips through the pages of the app against the fake Studio
+var e2eChecks = []e2eCheck{
+	{"upload and finish", checkUploadAndFinish},
+	{"existing files", checkExistingFiles},
+	{"rollback of a failed create", checkCreateRollback},
+	{"failed snapshot keeps the session", checkSnapshotFailure},
+	{"cut snapshot download keeps the session", checkCutDownload},
+	{"failed checkin keeps the snapshot", checkCheckinFailure},
+	{"failed reopen is retried by the next finish", checkReopenRetry},
+	{"stuck snapshot times out", checkSnapshotTimeout},
+	{"token refresh", checkTokenRefresh},
+	{"token rotated by another instance", checkRotatedToken},
+	{"transient failures are retried", checkRetries},
+	{"circuit opens while Studio is down", checkOutage},
+	{"Studio errors are explained", checkErrorMessages},
+	{"token deleted during a refresh stays deleted", checkRefreshAfterDelete},
+	{"logout revokes the grant", checkLogout},
+}
+
+// e2eHarness is the app served with a user logged in through the development auth server, whose Studio is the fake
+type e2eHarness struct {
+	studio    *studiotest.Server
+	auth      *studiotest.AuthServer
+	app       *httptest.Server
+	client    *http.Client
+	userID    string
+	csrfToken string
+	projectID string
+	folderID  int
+	previous  *environment
+}
+
+// TestEndToEnd runs the end-to-end suite. The app keeps its records in a BoltDB in a temporary directory; everything else, Studio included, is in memory.
+func TestEndToEnd(t *testing.T) {
+	ctx := context.Background()
+
+	store := &BoltDBStore{Path: filepath.Join(t.TempDir(), "my.db")}
+	if err := store.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	h, err := newE2EHarness(ctx, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.close(ctx)
+
+	// The checks run in order against the same app, like a user working through them
+	for _, check := range e2eChecks {
+		t.Run(check.name, func(t *testing.T) {
+			if err := check.run(ctx, h); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func newE2EHarness(ctx context.Context, store DataStore) (*e2eHarness, error) {
+	id := make([]byte, 4)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	snapshotDir, err := ioutil.TempDir("", "roundtripper-e2e")
+	if err != nil {
+		return nil, err
+	}
+
+	cookies, err := newCookieCodec([]string{hex.EncodeToString(secret)}, false)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &e2eHarness{
+		studio: studiotest.NewServer(),
+		auth:   studiotest.NewAuthServer("e2e", hex.EncodeToString(secret)),
+		userID: "e2e-" + hex.EncodeToString(id) + "@example.com",
+	}
+	auth := httptest.NewServer(h.auth)
+	h.studio.Authenticate = h.auth.Valid
+	h.studio.SnapshotDelay = 100 * time.Millisecond
+	fake := httptest.NewServer(h.studio)
+	h.studio.BaseURL = fake.URL
+
+	// Snapshots would otherwise be polled every few seconds, and failed calls retried after up to seconds
+	retry := studio.DefaultRetryPolicy
+	retry.BaseDelay = 10 * time.Millisecond
+	retry.MaxDelay = 50 * time.Millisecond
+
+	// The handlers find the app through env, which is put back once the suite is done
+	h.previous = env
+	env = &environment{
+		OAuthConfig: &StudioConfig{
+			Config: &oauth2.Config{
+				ClientID:     h.auth.ClientID,
+				ClientSecret: h.auth.ClientSecret,
+				Endpoint: oauth2.Endpoint{
+					AuthURL:  auth.URL + studiotest.AuthorizePath,
+					TokenURL: auth.URL + studiotest.TokenPath,
+				},
+			},
+			PKCE:      true,
+			RevokeURL: auth.URL + studiotest.RevokePath,
+		},
+		DataStore:   store,
+		StudioURL:   fake.URL + studiotest.APIPath,
+		SnapshotDir: snapshotDir,
+		Jobs:        newJobQueue(2, time.Hour),
+		Cookies:     cookies,
+		Breaker:     studio.NewBreaker(3, 200*time.Millisecond),
+
+		RetryPolicy:          retry,
+		SnapshotPollInterval: 20 * time.Millisecond,
+		SnapshotTimeout:      5 * time.Second,
+	}
+	h.app = httptest.NewServer(routes())
+	env.OAuthConfig.RedirectURL = h.app.URL + "/callback"
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	h.client = &http.Client{
+		Jar: jar,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+		Timeout: 30 * time.Second,
+	}
+
+	if err := h.login(); err != nil {
+		return nil, fmt.Errorf("Logging in: %v", err)
+	}
+
+	h.projectID = h.studio.AddProject("End-to-end")
+	h.folderID = h.studio.AddFolder(h.projectID, studio.RootFolderID, "Drawings")
+
+	return h, nil
+}
+
+// login goes through the OAuth flow as a browser would, authorizing as the harness user on the development auth server
+func (h *e2eHarness) login() error {
+	resp, err := h.client.Get(h.app.URL + "/oauth")
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	authorize, err := resp.Location()
+	if err != nil {
+		return fmt.Errorf("The login did not redirect to the auth server: %v", err)
+	}
+
+	approve := url.Values{"query": {authorize.RawQuery}, "userName": {h.userID}}
+	authorize.RawQuery = ""
+	resp, err = h.client.PostForm(authorize.String(), approve)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	callback, err := resp.Location()
+	if err != nil {
+		return fmt.Errorf("The auth server did not redirect back: %s", resp.Status)
+	}
+
+	resp, err = h.client.Get(callback.String())
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound || resp.Header.Get("Location") != "/" {
+		return fmt.Errorf("The callback answered %s to %s", resp.Status, resp.Header.Get("Location"))
+	}
+
+	// The forms of the app need the CSRF token of the new login session
+	req, _ := http.NewRequest("GET", h.app.URL, nil)
+	for _, c := range h.client.Jar.Cookies(req.URL) {
+		req.AddCookie(c)
+	}
+	session, err := currentLoginSession(req)
+	if err != nil {
+		return err
+	}
+	if session.UserID != h.userID {
+		return fmt.Errorf("Logged in as %s, want %s", session.UserID, h.userID)
+	}
+	h.csrfToken = env.Cookies.CSRFToken(session.ID)
+
+	return nil
+}
+
+// get fetches a page of the app, returning the status code
+func (h *e2eHarness) get(path string) (int, error) {
+	resp, err := h.client.Get(h.app.URL + path)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func (h *e2eHarness) close(ctx context.Context) {
+	h.app.Close()
+	env.DataStore.DeleteUser(ctx, h.userID)
+	os.RemoveAll(env.SnapshotDir)
+	env = h.previous
+}
+
+// post submits a form to a page of the app, along with the CSRF token
+func (h *e2eHarness) post(path, contentType string, body *bytes.Buffer) (*http.Response, error) {
+	req, err := http.NewRequest("POST", h.app.URL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-CSRF-Token", h.csrfToken)
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("POST %s: %s", path, resp.Status)
+	}
+	return resp, nil
+}
+
+// create submits the home page form and returns the round-trip it made. files are uploaded when there are any.
+func (h *e2eHarness) create(ctx context.Context, form url.Values, files map[string][]byte) (*RoundTrip, error) {
+	before, err := env.DataStore.ListUserRoundTrips(ctx, h.userID)
+	if err != nil {
+		return nil, err
+	}
+
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	form.Set("project", h.projectID)
+	for name, values := range form {
+		for _, v := range values {
+			w.WriteField(name, v)
+		}
+	}
+	for name, content := range files {
+		part, err := w.CreateFormFile("sessionFile", name)
+		if err != nil {
+			return nil, err
+		}
+		part.Write(content)
+	}
+	w.Close()
+
+	if _, err := h.post("/create", w.FormDataContentType(), body); err != nil {
+		return nil, err
+	}
+
+	after, err := env.DataStore.ListUserRoundTrips(ctx, h.userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, rt := range after {
+		if !containsRoundTrip(before, rt.ID) {
+			return rt, nil
+		}
+	}
+	return nil, errors.New("The create did not save a round-trip")
+}
+
+// finish submits the finish form of a round-trip and waits for the job to end
+func (h *e2eHarness) finish(ctx context.Context, rt *RoundTrip) (*RoundTrip, error) {
+	form := url.Values{"roundTripId": {rt.ID}}
+	resp, err := h.post("/finish", "application/x-www-form-urlencoded", bytes.NewBufferString(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusSeeOther || !strings.HasPrefix(resp.Header.Get("Location"), "/finish/status") {
+		return nil, fmt.Errorf("Finish answered %s, want a redirect to its status", resp.Status)
+	}
+
+	deadline := time.Now().Add(30 * time.Second)
+	for time.Now().Before(deadline) {
+		if job, ok := env.Jobs.Get(rt.ID, h.userID); !ok || job.Done() {
+			return env.DataStore.GetRoundTrip(ctx, rt.ID)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return nil, errors.New("The finish did not end in time")
+}
+
+// expectCheckedOut checks that the round-trip is active with its files checked out to its Session
+func (h *e2eHarness) expectCheckedOut(rt *RoundTrip) error {
+	if rt.State != roundTripActive || rt.Step != stepCheckedOut {
+		return fmt.Errorf("Create left the round-trip %s at %s: %s", rt.State, rt.Step, rt.Error)
+	}
+	if s, ok := h.studio.Session(rt.SessionID); !ok || s.Status != "Active" {
+		return fmt.Errorf("The Session %s is missing or not active", rt.SessionID)
+	}
+	for _, f := range rt.Files {
+		pf, _, _, ok := h.studio.File(rt.ProjectID, f.FileProjectID)
+		if !ok || !pf.CheckedOut {
+			return fmt.Errorf("%s is not checked out", f.Name)
+		}
+	}
+	return nil
+}
+
+// expectComplete checks that a finished round-trip checked the snapshots with their markups in, and cleaned up after itself
+func (h *e2eHarness) expectComplete(rt *RoundTrip, want map[int][]byte) error {
+	if rt.State != roundTripComplete {
+		return fmt.Errorf("Finish left the round-trip %s: %s", rt.State, rt.Error)
+	}
+	if _, ok := h.studio.Session(rt.SessionID); ok {
+		return errors.New("The Session was not deleted")
+	}
+	for _, f := range rt.Files {
+		pf, content, revisions, ok := h.studio.File(rt.ProjectID, f.FileProjectID)
+		if !ok {
+			return fmt.Errorf("%s is gone from the Project", f.Name)
+		}
+		if pf.CheckedOut || revisions != 1 {
+			return fmt.Errorf("%s was not checked in", f.Name)
+		}
+		if !bytes.Equal(content, want[f.FileProjectID]) {
+			return fmt.Errorf("The revision of %s is %q, want %q", f.Name, content, want[f.FileProjectID])
+		}
+		if f.Step != fileShared || f.ShareLink == "" {
+			return fmt.Errorf("%s stopped at %s without a share link", f.Name, f.Step)
+		}
+	}
+	if _, err := os.Stat(rt.snapshotPath(rt.Files[0])); !os.IsNotExist(err) {
+		return errors.New("The snapshots were not removed")
+	}
+	return nil
+}
+
+// markup adds a markup to every file of the round-trip and returns the revisions the finish should check in
+func (h *e2eHarness) markup(rt *RoundTrip) (map[int][]byte, error) {
+	want := map[int][]byte{}
+	for _, f := range rt.Files {
+		_, content, _, _ := h.studio.File(rt.ProjectID, f.FileProjectID)
+		markup := "cloud on " + f.Name
+		if err := h.studio.Markup(rt.SessionID, f.FileSessionID, markup); err != nil {
+			return nil, err
+		}
+		want[f.FileProjectID] = append(append([]byte(nil), content...), []byte("% markup: "+markup+"\n")...)
+	}
+	return want, nil
+}
+
+func checkUploadAndFinish(ctx context.Context, h *e2eHarness) error {
+	form := url.Values{
+		"session":     {"E2E upload"},
+		"folder":      {strconv.Itoa(h.folderID)},
+		"source":      {"upload"},
+		"datedFolder": {"on"},
+	}
+	files := map[string][]byte{
+		"first.pdf":  []byte("%PDF-1.4\n% first\n"),
+		"second.pdf": []byte("%PDF-1.4\n% second\n"),
+	}
+
+	rt, err := h.create(ctx, form, files)
+	if err != nil {
+		return err
+	}
+	if err := h.expectCheckedOut(rt); err != nil {
+		return err
+	}
+	for _, f := range rt.Files {
+		pf, content, _, _ := h.studio.File(rt.ProjectID, f.FileProjectID)
+		if !bytes.Equal(content, files[f.Name]) {
+			return fmt.Errorf("%s was uploaded as %q", f.Name, content)
+		}
+		// The fake only confirms an upload that matches the size and CRC it was given
+		if pf.CRC == "" {
+			return fmt.Errorf("%s was uploaded without a CRC", f.Name)
+		}
+	}
+
+	want, err := h.markup(rt)
+	if err != nil {
+		return err
+	}
+
+	rt, err = h.finish(ctx, rt)
+	if err != nil {
+		return err
+	}
+	return h.expectComplete(rt, want)
+}
+
+func checkExistingFiles(ctx context.Context, h *e2eHarness) error {
+	fileID := h.studio.AddFile(h.projectID, h.folderID, "existing.pdf", []byte("%PDF-1.4\n% existing\n"))
+
+	form := url.Values{
+		"session":     {"E2E existing"},
+		"source":      {"existing"},
+		"projectFile": {strconv.Itoa(fileID)},
+	}
+	rt, err := h.create(ctx, form, nil)
+	if err != nil {
+		return err
+	}
+	if err := h.expectCheckedOut(rt); err != nil {
+		return err
+	}
+
+	want, err := h.markup(rt)
+	if err != nil {
+		return err
+	}
+
+	rt, err = h.finish(ctx, rt)
+	if err != nil {
+		return err
+	}
+	return h.expectComplete(rt, want)
+}
+
+func checkCreateRollback(ctx context.Context, h *e2eHarness) error {
+	first := h.studio.AddFile(h.projectID, h.folderID, "rollback-1.pdf", []byte("%PDF-1.4\n% 1\n"))
+	second := h.studio.AddFile(h.projectID, h.folderID, "rollback-2.pdf", []byte("%PDF-1.4\n% 2\n"))
+	sessions := len(h.studio.Sessions())
+
+	h.studio.FailNext("POST", fmt.Sprintf("projects/%s/files/%d/checkout-to-session", h.projectID, second), http.StatusInternalServerError)
+
+	form := url.Values{
+		"session":     {"E2E rollback"},
+		"source":      {"existing"},
+		"projectFile": {strconv.Itoa(first), strconv.Itoa(second)},
+	}
+	rt, err := h.create(ctx, form, nil)
+	if err != nil {
+		return err
+	}
+
+	if rt.State != roundTripFailed {
+		return fmt.Errorf("A failed checkout left the round-trip %s", rt.State)
+	}
+	for _, c := range rt.Compensations {
+		if !c.Done {
+			return fmt.Errorf("%s was not done: %s", c, c.Error)
+		}
+	}
+	if len(h.studio.Sessions()) != sessions {
+		return errors.New("The Session was not deleted")
+	}
+	if pf, _, _, _ := h.studio.File(h.projectID, first); pf.CheckedOut {
+		return errors.New("The checkout of the first file was not undone")
+	}
+	return nil
+}
+
+func checkSnapshotFailure(ctx context.Context, h *e2eHarness) error {
+	fileID := h.studio.AddFile(h.projectID, h.folderID, "snapshot.pdf", []byte("%PDF-1.4\n% snapshot\n"))
+
+	form := url.Values{
+		"session":     {"E2E snapshot"},
+		"source":      {"existing"},
+		"projectFile": {strconv.Itoa(fileID)},
+	}
+	rt, err := h.create(ctx, form, nil)
+	if err != nil {
+		return err
+	}
+	if err := h.expectCheckedOut(rt); err != nil {
+		return err
+	}
+
+	want, err := h.markup(rt)
+	if err != nil {
+		return err
+	}
+
+	h.studio.FailNext("GET", fmt.Sprintf("sessions/%s/files/%d/snapshot", rt.SessionID, rt.Files[0].FileSessionID), http.StatusInternalServerError)
+	rt, err = h.finish(ctx, rt)
+	if err != nil {
+		return err
+	}
+	if rt.Error == "" {
+		return errors.New("The failed snapshot was not reported")
+	}
+	// Nothing may be lost: the Session is reopened for another attempt
+	rt.Error = ""
+	if err := h.expectCheckedOut(rt); err != nil {
+		return err
+	}
+
+	rt, err = h.finish(ctx, rt)
+	if err != nil {
+		return err
+	}
+	return h.expectComplete(rt, want)
+}
+
+func checkCheckinFailure(ctx context.Context, h *e2eHarness) error {
+	first := h.studio.AddFile(h.projectID, h.folderID, "checkin-1.pdf", []byte("%PDF-1.4\n% checkin 1\n"))
+	second := h.studio.AddFile(h.projectID, h.folderID, "checkin-2.pdf", []byte("%PDF-1.4\n% checkin 2\n"))
+
+	form := url.Values{
+		"session":     {"E2E checkin"},
+		"source":      {"existing"},
+		"projectFile": {strconv.Itoa(first), strconv.Itoa(second)},
+	}
+	rt, err := h.create(ctx, form, nil)
+	if err != nil {
+		return err
+	}
+	want, err := h.markup(rt)
+	if err != nil {
+		return err
+	}
+
+	// Once the Session is gone the snapshot is the only copy of the markups on the file that failed
+	h.studio.FailNext("POST", fmt.Sprintf("projects/%s/files/%d/checkin", h.projectID, second), http.StatusInternalServerError)
+	rt, err = h.finish(ctx, rt)
+	if err != nil {
+		return err
+	}
+	if rt.State != roundTripFailed || rt.Step != stepSessionDeleted {
+		return fmt.Errorf("A failed checkin left the round-trip %s at %s", rt.State, rt.Step)
+	}
+	for _, f := range rt.Files {
+		_, err := os.Stat(rt.snapshotPath(f))
+		if kept := err == nil; kept != f.Failed() {
+			return fmt.Errorf("The snapshot of %s was kept: %v, failed: %v", f.Name, kept, f.Failed())
+		}
+	}
+
+	// Finishing again checks in only the file that failed
+	rt, err = h.finish(ctx, rt)
+	if err != nil {
+		return err
+	}
+	return h.expectComplete(rt, want)
+}
+
+func checkReopenRetry(ctx context.Context, h *e2eHarness) error {
+	fileID := h.studio.AddFile(h.projectID, h.folderID, "reopen.pdf", []byte("%PDF-1.4\n% reopen\n"))
+
+	form := url.Values{
+		"session":     {"E2E reopen"},
+		"source":      {"existing"},
+		"projectFile": {strconv.Itoa(fileID)},
+	}
+	rt, err := h.create(ctx, form, nil)
+	if err != nil {
+		return err
+	}
+	want, err := h.markup(rt)
+	if err != nil {
+		return err
+	}
+
+	// Leave the round-trip as a finish does when neither a snapshot nor reopening the Session worked
+	client, err := userStudioClient(h.userID)
+	if err != nil {
+		return err
+	}
+	if _, err := client.Sessions.SetStatus(ctx, rt.SessionID, "Finalizing"); err != nil {
+		return err
+	}
+	rt.Error = "The Session could not be reopened; finishing it again will retry."
+	rt.compensate(&Compensation{Action: compensateReopenSession, SessionID: rt.SessionID, Name: rt.SessionName, Error: "Injected failure"})
+	if err := saveRoundTrip(rt); err != nil {
+		return err
+	}
+
+	// The reopen is retried before anything else, so this failure hits it rather than setting the Session to Finalizing again
+	h.studio.FailNext("PUT", "sessions/"+rt.SessionID, http.StatusInternalServerError)
+	rt, err = h.finish(ctx, rt)
+	if err != nil {
+		return err
+	}
+	if rt.State != roundTripActive || !rt.pendingCompensations() {
+		return fmt.Errorf("A failed reopen left the round-trip %s without a pending reopen: %s", rt.State, rt.Error)
+	}
+
+	rt, err = h.finish(ctx, rt)
+	if err != nil {
+		return err
+	}
+	return h.expectComplete(rt, want)
+}
+
+func checkSnapshotTimeout(ctx context.Context, h *e2eHarness) error {
+	fileID := h.studio.AddFile(h.projectID, h.folderID, "stuck.pdf", []byte("%PDF-1.4\n% stuck\n"))
+
+	form := url.Values{
+		"session":     {"E2E stuck"},
+		"source":      {"existing"},
+		"projectFile": {strconv.Itoa(fileID)},
+	}
+	rt, err := h.create(ctx, form, nil)
+	if err != nil {
+		return err
+	}
+	want, err := h.markup(rt)
+	if err != nil {
+		return err
+	}
+
+	// The fake takes longer over every snapshot than the finish is now willing to wait
+	timeout := env.SnapshotTimeout
+	env.SnapshotTimeout = h.studio.SnapshotDelay / 2
+	rt, err = h.finish(ctx, rt)
+	env.SnapshotTimeout = timeout
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(rt.Error, "could not be snapshotted") || !strings.Contains(rt.Files[0].Error, "not ready after") {
+		return fmt.Errorf("The stuck snapshot was reported as %q, %q", rt.Error, rt.Files[0].Error)
+	}
+	rt.Error = ""
+	if err := h.expectCheckedOut(rt); err != nil {
+		return err
+	}
+
+	rt, err = h.finish(ctx, rt)
+	if err != nil {
+		return err
+	}
+	return h.expectComplete(rt, want)
+}
+
+func checkCutDownload(ctx context.Context, h *e2eHarness) error {
+	fileID := h.studio.AddFile(h.projectID, h.folderID, "download.pdf", []byte("%PDF-1.4\n% download\n"))
+
+	form := url.Values{
+		"session":     {"E2E download"},
+		"source":      {"existing"},
+		"projectFile": {strconv.Itoa(fileID)},
+	}
+	rt, err := h.create(ctx, form, nil)
+	if err != nil {
+		return err
+	}
+	want, err := h.markup(rt)
+	if err != nil {
+		return err
+	}
+
+	// Half a snapshot must never become the new revision
+	h.studio.CutNextDownload()
+	rt, err = h.finish(ctx, rt)
+	if err != nil {
+		return err
+	}
+	if rt.Error == "" {
+		return errors.New("The cut download was not reported")
+	}
+	if _, _, revisions, _ := h.studio.File(h.projectID, fileID); revisions != 0 {
+		return errors.New("The cut snapshot was checked in")
+	}
+	rt.Error = ""
+	if err := h.expectCheckedOut(rt); err != nil {
+		return err
+	}
+
+	rt, err = h.finish(ctx, rt)
+	if err != nil {
+		return err
+	}
+	return h.expectComplete(rt, want)
+}
+
+func checkTokenRefresh(ctx context.Context, h *e2eHarness) error {
+	stale, err := env.DataStore.GetToken(ctx, h.userID)
+	if err != nil {
+		return err
+	}
+
+	// Let the token expire, on the auth server and in the app
+	h.auth.ExpireAccessTokens()
+	expired := *stale
+	expired.Expiry = time.Now().Add(-time.Minute)
+	if err := env.DataStore.StoreToken(ctx, h.userID, &expired); err != nil {
+		return err
+	}
+
+	// Pages loaded at the same time must share a single refresh, as the refresh token only works once
+	refreshes := h.auth.Refreshes()
+	statuses := make(chan int, 5)
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		go func() {
+			status, err := h.get("/")
+			statuses <- status
+			errs <- err
+		}()
+	}
+	for i := 0; i < 5; i++ {
+		if err := <-errs; err != nil {
+			return err
+		}
+		if status := <-statuses; status != http.StatusOK {
+			return fmt.Errorf("The home page answered %d after the token expired", status)
+		}
+	}
+
+	if n := h.auth.Refreshes() - refreshes; n != 1 {
+		return fmt.Errorf("The token was refreshed %d times, want once", n)
+	}
+	if n := h.auth.ReusedRefreshes(); n != 0 {
+		return fmt.Errorf("A spent refresh token was used %d times", n)
+	}
+
+	token, err := env.DataStore.GetToken(ctx, h.userID)
+	if err != nil {
+		return err
+	}
+	if token.RefreshToken == stale.RefreshToken || !h.auth.Valid(token.AccessToken) {
+		return errors.New("The refreshed token was not stored")
+	}
+	return nil
+}
+
+func checkRotatedToken(ctx context.Context, h *e2eHarness) error {
+	stale, err := env.DataStore.GetToken(ctx, h.userID)
+	if err != nil {
+		return err
+	}
+
+	// Another instance refreshes the token and stores the new one
+	expired := *stale
+	expired.Expiry = time.Now().Add(-time.Minute)
+	rotated, err := env.OAuthConfig.Config.TokenSource(ctx, &expired).Token()
+	if err != nil {
+		return err
+	}
+	if err := env.DataStore.StoreToken(ctx, h.userID, rotated); err != nil {
+		return err
+	}
+
+	// A token source still holding the old token, like a running finish job, must pick up the new token rather than spend the old refresh token again
+	refreshes := h.auth.Refreshes()
+	token, err := env.OAuthConfig.TokenSource(ctx, h.userID, &expired).Token()
+	if err != nil {
+		return err
+	}
+	if token.AccessToken != rotated.AccessToken {
+		return errors.New("The token rotated by the other instance was not used")
+	}
+	if h.auth.Refreshes() != refreshes || h.auth.ReusedRefreshes() != 0 {
+		return errors.New("The old refresh token was spent again")
+	}
+	return nil
+}
+
+func checkRetries(ctx context.Context, h *e2eHarness) error {
+	fileID := h.studio.AddFile(h.projectID, h.folderID, "retry.pdf", []byte("%PDF-1.4\n% retry\n"))
+
+	// Studio turning a create away is retried after the Retry-After, as is a failed gateway on a lookup
+	h.studio.FailNext("POST", "sessions", http.StatusTooManyRequests)
+	h.studio.FailNext("GET", "projects", http.StatusBadGateway)
+
+	if status, err := h.get("/"); err != nil || status != http.StatusOK {
+		return fmt.Errorf("The home page answered %d after a failed gateway", status)
+	}
+
+	form := url.Values{
+		"session":     {"E2E retry"},
+		"source":      {"existing"},
+		"projectFile": {strconv.Itoa(fileID)},
+	}
+	rt, err := h.create(ctx, form, nil)
+	if err != nil {
+		return err
+	}
+	if err := h.expectCheckedOut(rt); err != nil {
+		return err
+	}
+
+	// A checkout may have happened before a failed gateway, so it is not sent again
+	second := h.studio.AddFile(h.projectID, h.folderID, "retry-2.pdf", []byte("%PDF-1.4\n% retry 2\n"))
+	h.studio.FailNext("POST", fmt.Sprintf("projects/%s/files/%d/checkout-to-session", h.projectID, second), http.StatusBadGateway)
+	form.Set("session", "E2E no retry")
+	form["projectFile"] = []string{strconv.Itoa(second)}
+	rt, err = h.create(ctx, form, nil)
+	if err != nil {
+		return err
+	}
+	if rt.State != roundTripFailed {
+		return fmt.Errorf("A checkout was retried after a failed gateway, leaving the round-trip %s", rt.State)
+	}
+	return nil
+}
+
+func checkOutage(ctx context.Context, h *e2eHarness) error {
+	h.studio.SetDown(true)
+
+	// Each page load counts once however many times it was retried, so the circuit opens after Threshold of them and nothing more is sent to Studio
+	for i := 1; i <= env.Breaker.Threshold; i++ {
+		if status, err := h.get("/"); err != nil || status != http.StatusFound {
+			h.studio.SetDown(false)
+			return fmt.Errorf("The home page answered %d while Studio was down", status)
+		}
+		if open, _ := env.Breaker.State(); open != (i == env.Breaker.Threshold) {
+			h.studio.SetDown(false)
+			return fmt.Errorf("The circuit was open: %v after %d page loads", open, i)
+		}
+	}
+	if open, _ := env.Breaker.State(); !open {
+		h.studio.SetDown(false)
+		return errors.New("The circuit did not open")
+	}
+
+	resp, err := h.client.Get(h.app.URL + "/roundtrips")
+	if err != nil {
+		h.studio.SetDown(false)
+		return err
+	}
+	page, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(page), "Studio is not responding") {
+		h.studio.SetDown(false)
+		return errors.New("The round-trips page does not show that Studio is down")
+	}
+
+	// Once Studio is back, the trial call after the cooldown closes the circuit
+	h.studio.SetDown(false)
+	time.Sleep(env.Breaker.Cooldown)
+	if status, err := h.get("/"); err != nil || status != http.StatusOK {
+		return fmt.Errorf("The home page answered %d once Studio was back", status)
+	}
+	if open, _ := env.Breaker.State(); open {
+		return errors.New("The circuit did not close")
+	}
+	return nil
+}
+
+func checkErrorMessages(ctx context.Context, h *e2eHarness) error {
+	fileID := h.studio.AddFile(h.projectID, h.folderID, "conflict.pdf", []byte("%PDF-1.4\n% conflict\n"))
+
+	form := url.Values{
+		"session":     {"E2E conflict"},
+		"source":      {"existing"},
+		"projectFile": {strconv.Itoa(fileID)},
+	}
+	if _, err := h.create(ctx, form, nil); err != nil {
+		return err
+	}
+
+	// A second Session cannot have the file while the first has it checked out
+	form.Set("session", "E2E conflict 2")
+	rt, err := h.create(ctx, form, nil)
+	if err != nil {
+		return err
+	}
+	if rt.State != roundTripFailed {
+		return fmt.Errorf("Checking out a checked out file left the round-trip %s", rt.State)
+	}
+	if !strings.Contains(rt.Error, "conflict.pdf: The file is already checked out") || !strings.Contains(rt.Error, "Studio request fake-") {
+		return fmt.Errorf("The conflict was reported as %q", rt.Error)
+	}
+
+	// The error page shows the message it was sent, and only that
+	resp, err := h.post("/finish", "application/x-www-form-urlencoded", bytes.NewBufferString("roundTripId=unknown"))
+	if err != nil {
+		return err
+	}
+	if resp.Header.Get("Location") != "/error" {
+		return fmt.Errorf("An unknown round-trip was sent to %q", resp.Header.Get("Location"))
+	}
+	for _, want := range []string{"Unknown round-trip", "Something went wrong"} {
+		resp, err := h.client.Get(h.app.URL + "/error?description=Made+up")
+		if err != nil {
+			return err
+		}
+		page, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if !strings.Contains(string(page), want) || strings.Contains(string(page), "Made up") {
+			return fmt.Errorf("The error page does not say %q", want)
+		}
+	}
+	return nil
+}
+
+func checkRefreshAfterDelete(ctx context.Context, h *e2eHarness) error {
+	stale, err := env.DataStore.GetToken(ctx, h.userID)
+	if err != nil {
+		return err
+	}
+
+	// A job still holding the token refreshes it after the user was signed out, here without the grant being revoked
+	if err := env.DataStore.DeleteToken(ctx, h.userID); err != nil {
+		return err
+	}
+	expired := *stale
+	expired.Expiry = time.Now().Add(-time.Minute)
+	if _, err := env.OAuthConfig.TokenSource(ctx, h.userID, &expired).Token(); err == nil {
+		return errors.New("The token of a signed out user was refreshed")
+	}
+	if _, err := env.DataStore.GetToken(ctx, h.userID); !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("The refresh stored a token for the signed out user: %v", err)
+	}
+
+	// The checks after this one need the user logged in again
+	return h.login()
+}
+
+func checkLogout(ctx context.Context, h *e2eHarness) error {
+	token, err := env.DataStore.GetToken(ctx, h.userID)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.post("/logout", "application/x-www-form-urlencoded", &bytes.Buffer{})
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusSeeOther || resp.Header.Get("Location") != "/login" {
+		return fmt.Errorf("Logout answered %s", resp.Status)
+	}
+
+	if h.auth.Valid(token.AccessToken) {
+		return errors.New("The grant was not revoked")
+	}
+	if _, err := env.DataStore.GetToken(ctx, h.userID); !errors.Is(err, ErrNotFound) {
+		return errors.New("The token was not deleted")
+	}
+	if status, err := h.get("/"); err != nil || status != http.StatusFound {
+		return fmt.Errorf("The home page answered %d after logging out, want a redirect to log in", status)
+	}
+	return nil
+}
+
+func containsRoundTrip(list []*RoundTrip, id string) bool {
+	for _, rt := range list {
+		if rt.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -8,11 +8,25 @@ import (
 	"errors"
 	"fmt"
 	"html/template"
-	"io/ioutil"
 	"net/http"
 	"net/url"
+	"time"
+	"unicode/utf8"
+
+	"bluebeam/gosessionroundtripper/studio"
+
+	"golang.org/x/oauth2"
 )
 
+// errorCookie carries the message from redirectToError to the error page. Keeping it out of the URL means a link cannot make the app show a message of someone else's choosing.
+const errorCookie = "error"
+
+// errorLifetime is how long the message waits for the error page
+const errorLifetime = time.Minute
+
+// errorCookieMessageLimit is the longest message put in the error cookie. Sealed and encoded it stays well under the 4KB browsers keep of a cookie.
+const errorCookieMessageLimit = 1500
+
 func errorPage(w http.ResponseWriter, r *http.Request) {
 	html, err := Asset("assets/error.html")
 	if err != nil {
@@ -23,28 +37,136 @@ func errorPage(w http.ResponseWriter, r *http.Request) {
 	t, _ := template.New("error").Parse(string(html))
 	errorData := struct {
 		Description string
+		Rollback    []*Compensation
+		Degraded    string
 	}{}
 
-	desc, _ := url.QueryUnescape(r.URL.Query().Get("description"))
+	// The message is only shown once
+	desc, err := env.Cookies.Value(r, errorCookie, errorLifetime)
+	if err != nil {
+		desc = "Something went wrong. Go back and try again."
+	}
+	env.Cookies.ClearCookie(w, errorCookie)
 
 	errorData.Description = desc
+	errorData.Degraded = studioDegraded()
 
 	t.Execute(w, errorData)
 }
 
+// redirectToError sends the user to the error page, which explains err. The details of err are only logged.
 func redirectToError(w http.ResponseWriter, r *http.Request, err error) {
 	fmt.Println(err)
-	http.Redirect(w, r, "/error?description="+url.QueryEscape(err.Error()), http.StatusFound)
+	if err := env.Cookies.SetCookie(w, errorCookie, truncateMessage(errorMessage(err), errorCookieMessageLimit), errorLifetime); err != nil {
+		fmt.Println(err)
+	}
+	http.Redirect(w, r, "/error", http.StatusFound)
 }
 
-func checkHTTPResponse(resp *http.Response) (bool, error) {
-	if resp.StatusCode >= http.StatusBadRequest {
-		errBytes, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return false, err
+// truncateMessage cuts message down to at most limit bytes, on a character boundary, marking where it was cut
+func truncateMessage(message string, limit int) string {
+	if len(message) <= limit {
+		return message
+	}
+
+	const ellipsis = "…"
+	cut := limit - len(ellipsis)
+	for cut > 0 && !utf8.RuneStart(message[cut]) {
+		cut--
+	}
+	return message[:cut] + ellipsis
+}
+
+// renderError shows err directly, without a redirect, along with the outcome of the rollback it caused
+func renderError(w http.ResponseWriter, r *http.Request, err error, rollback []*Compensation) {
+	fmt.Println(err)
+
+	html, assetErr := Asset("assets/error.html")
+	if assetErr != nil {
+		redirectToError(w, r, err)
+		return
+	}
+
+	t, _ := template.New("error").Parse(string(html))
+	errorData := struct {
+		Description string
+		Rollback    []*Compensation
+		Degraded    string
+	}{errorMessage(err), rollback, studioDegraded()}
+
+	t.Execute(w, errorData)
+}
+
+// studioDegraded explains the outage while the circuit to Studio is open, and is empty otherwise
+func studioDegraded() string {
+	if env.Breaker == nil {
+		return ""
+	}
+	open, retryAt := env.Breaker.State()
+	if !open {
+		return ""
+	}
+	return fmt.Sprintf("Studio is not responding, so calls to it are paused until %s. Round-trips are saved and can be finished once Studio is back.", retryAt.Local().Format(time.Kitchen))
+}
+
+// fileError is a failure of a single file, told to the user along with the name of the file
+type fileError struct {
+	name string
+	err  error
+}
+
+func (e *fileError) Error() string {
+	return e.name + ": " + e.err.Error()
+}
+
+func (e *fileError) Unwrap() error {
+	return e.err
+}
+
+// errorMessage is what the user is told about err. A failed Studio call is explained by what went wrong rather than by what Studio answered, with the request id so it can be looked up.
+func errorMessage(err error) string {
+	var fileErr *fileError
+	if errors.As(err, &fileErr) {
+		return fileErr.name + ": " + errorMessage(fileErr.err)
+	}
+
+	var apiErr *studio.APIError
+	if !errors.As(err, &apiErr) {
+		var retrieveErr *oauth2.RetrieveError
+		var urlErr *url.Error
+		switch {
+		case errors.Is(err, studio.ErrUnavailable):
+			return "Studio is not responding right now. Try again in a few minutes."
+		case errors.As(err, &retrieveErr):
+			return "Your Studio login has expired. Log out and log in again."
+		case errors.As(err, &urlErr):
+			return "Studio could not be reached. Try again in a few minutes."
 		}
-		return false, errors.New(resp.Status + " " + string(errBytes))
+		return err.Error()
 	}
 
-	return true, nil
+	var message string
+	switch {
+	case errors.Is(err, studio.ErrUnavailable):
+		message = "Studio is not responding right now. Try again in a few minutes."
+	case errors.Is(err, studio.ErrAlreadyCheckedOut):
+		message = "The file is already checked out to a Session. Check it in or undo the checkout in Studio, then try again."
+	case errors.Is(err, studio.ErrNotCheckedOut):
+		message = "The file is no longer checked out to the Session. It may have been checked in or the checkout undone in Studio."
+	case errors.Is(err, studio.ErrSessionNotFound):
+		message = "The Session no longer exists in Studio. It may have been deleted or finished outside of the app."
+	case errors.Is(err, studio.ErrUnauthorized):
+		message = "Studio did not accept your login. Log out and log in again."
+	case errors.Is(err, studio.ErrForbidden):
+		message = "You do not have permission to do this in Studio."
+	case errors.Is(err, studio.ErrNotFound):
+		message = "The Project, folder or file could not be found in Studio. It may have been deleted."
+	default:
+		message = "Studio could not complete the request: " + apiErr.Message
+	}
+
+	if apiErr.RequestID != "" {
+		message += fmt.Sprintf(" (Studio request %s)", apiErr.RequestID)
+	}
+	return message
 }
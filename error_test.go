@@ -0,0 +1,52 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// TestRedirectToErrorLongMessage checks that a message too long for a cookie reaches the error page cut short rather than being dropped by the browser
+func TestRedirectToErrorLongMessage(t *testing.T) {
+	cookies, err := newCookieCodec([]string{"secret"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := env
+	env = &environment{Cookies: cookies}
+	defer func() { env = previous }()
+
+	// Two byte characters make sure the message is not cut in the middle of one
+	message := strings.Repeat("Ü", 3000)
+
+	w := httptest.NewRecorder()
+	redirectToError(w, httptest.NewRequest("POST", "/create", nil), errors.New(message))
+
+	header := w.Header().Get("Set-Cookie")
+	if header == "" {
+		t.Fatal("No error cookie was set")
+	}
+	if len(header) >= 4096 {
+		t.Errorf("The error cookie is %d bytes, more than a browser keeps", len(header))
+	}
+
+	r := httptest.NewRequest("GET", "/error", nil)
+	r.Header.Set("Cookie", strings.SplitN(header, ";", 2)[0])
+	got, err := env.Cookies.Value(r, errorCookie, errorLifetime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !utf8.ValidString(got) || !strings.HasSuffix(got, "…") || !strings.HasPrefix(message, strings.TrimSuffix(got, "…")) {
+		t.Errorf("The message was not cut short cleanly: %q", got[len(got)-20:])
+	}
+	if w.Code != http.StatusFound {
+		t.Errorf("redirectToError answered %d", w.Code)
+	}
+}
@@ -5,122 +5,495 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"html/template"
+	"io"
 	"net/http"
-	"strconv"
+	"os"
+	"path/filepath"
 	"time"
+
+	"bluebeam/gosessionroundtripper/studio"
 )
 
+// finishPage queues the finish workflow and sends the user to a page that follows its progress
 func finishPage(w http.ResponseWriter, r *http.Request) {
-	client := getOAuthClient(r.Context())
+	ctx := r.Context()
+	u := ctx.Value("user").(user)
 
-	sessionID := r.FormValue("sessionId")
-	projectID := r.FormValue("projectId")
-	fileSessionID, _ := strconv.ParseInt(r.FormValue("fileSessionId"), 10, 32)
-	fileProjectID, _ := strconv.ParseInt(r.FormValue("fileProjectId"), 10, 32)
+	rt, err := env.DataStore.GetRoundTrip(ctx, r.FormValue("roundTripId"))
+	if err != nil || rt.UserID != u.UserID {
+		redirectToError(w, r, errors.New("Unknown round-trip"))
+		return
+	}
 
-	// Set Session to Finalizing to boot people
-	_, err := setSessionStatus(client, sessionID, "Finalizing")
+	if rt.State != roundTripActive && !rt.checkinRetryable() {
+		redirectToError(w, r, fmt.Errorf("The session %s is %s and cannot be finished", rt.SessionName, rt.State))
+		return
+	}
+
+	// The job outlives the request so it cannot use the request context
+	client, err := newStudioClient(env.OAuthConfig.TokenSource(context.Background(), u.UserID, u.Token))
+	if err != nil {
+		redirectToError(w, r, err)
+		return
+	}
+
+	if err := startFinish(rt, client); err != nil {
+		redirectToError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, "/finish/status?id="+rt.ID, http.StatusSeeOther)
+}
+
+// startFinish checkpoints the round-trip as finishing and queues the job that finishes it
+func startFinish(rt *RoundTrip, client *studio.Client) error {
+	// The job is reserved first so a finish that is already running never has its record overwritten
+	job := newFinishJob(rt, client)
+	if err := env.Jobs.reserve(job); err != nil {
+		return err
+	}
+	// The lease keeps another instance from finishing or resuming the round-trip at the same time
+	lease, err := leaseRoundTrip(rt.ID)
+	if err != nil {
+		env.Jobs.remove(job)
+		return err
+	}
+	job.lease = lease
+
+	// Errors from a previous attempt are cleared so the files are retried, and put back if the job cannot be queued
+	state, message, compensations := rt.State, rt.Error, rt.Compensations
+	fileErrors := make([]string, len(rt.Files))
+	for i, f := range rt.Files {
+		fileErrors[i] = f.Error
+		f.Error = ""
+	}
+	rt.State = roundTripFinishing
+	rt.Error = ""
+	// Compensations an earlier attempt could not run, like reopening the Session, are kept for runFinish to retry
+	var pending []*Compensation
+	for _, c := range rt.Compensations {
+		if !c.Done {
+			pending = append(pending, c)
+		}
+	}
+	rt.Compensations = pending
+	if err := saveRoundTrip(rt); err != nil {
+		env.Jobs.remove(job)
+		lease.Release()
+		return err
+	}
+
+	if err := env.Jobs.submit(job); err != nil {
+		defer lease.Release()
+		rt.State, rt.Error, rt.Compensations = state, message, compensations
+		for i, f := range rt.Files {
+			f.Error = fileErrors[i]
+		}
+		saveRoundTrip(rt)
+		return err
+	}
+	return nil
+}
+
+// finishStatusPage shows the progress of a finish job. The page keeps itself up to date from finishEventsHandler.
+func finishStatusPage(w http.ResponseWriter, r *http.Request) {
+	u := r.Context().Value("user").(user)
+
+	progress, _, err := findProgress(r.Context(), r.URL.Query().Get("id"), u.UserID)
 	if err != nil {
 		redirectToError(w, r, err)
 		return
 	}
 
-	// Initiate Snapshot
-	err = startSnapshot(client, sessionID, int(fileSessionID))
+	html, err := Asset("assets/finish.html")
 	if err != nil {
 		redirectToError(w, r, err)
 		return
 	}
 
-	// Poll the snapshot status every 5 seconds until complete or an error
-	var snapshotResponse *SnapshotResponse
+	t, _ := template.New("finishSession").Parse(string(html))
+
+	t.Execute(w, progress)
+}
+
+// findProgress returns the progress of the running job for a round-trip, or of the stored record once the job is gone. changed is nil when there is no running job.
+func findProgress(ctx context.Context, id, userID string) (jobProgress, <-chan struct{}, error) {
+	if job, ok := env.Jobs.Get(id, userID); ok {
+		progress, changed := job.Progress()
+		return progress, changed, nil
+	}
+
+	rt, err := env.DataStore.GetRoundTrip(ctx, id)
+	if err != nil || rt.UserID != userID {
+		return jobProgress{}, nil, errors.New("Unknown round-trip")
+	}
+
+	return roundTripProgress(rt, rt.State != roundTripFinishing), nil, nil
+}
+
+// finishEventsHandler streams the progress of a finish job as Server-Sent Events until the job is done
+func finishEventsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	u := ctx.Value("user").(user)
+	id := r.URL.Query().Get("id")
+
+	if _, _, err := findProgress(ctx, id, u.UserID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
 
-outer:
 	for {
-		snapshotResponse, err = getSnapshotStatus(client, sessionID, int(fileSessionID))
+		progress, changed, err := findProgress(ctx, id, u.UserID)
 		if err != nil {
-			fmt.Println(err)
 			return
 		}
-		fmt.Println(snapshotResponse)
 
-		switch snapshotResponse.Status {
-		case "Complete":
-			break outer
-		case "Error":
-			redirectToError(w, r, errors.New("Shapshot error"))
+		data, err := json.Marshal(progress)
+		if err != nil {
 			return
+		}
 
+		event := "progress"
+		if progress.Done {
+			event = "done"
 		}
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+		flusher.Flush()
 
-		time.Sleep(5 * time.Second)
+		if progress.Done {
+			return
+		}
+
+		// Without a running job, e.g. while a restarted instance has yet to resume it, the record is polled instead
+		select {
+		case <-ctx.Done():
+			return
+		case <-changed:
+		case <-time.After(15 * time.Second):
+			// Resend the current state as a keep-alive so proxies do not drop the connection
+		}
 	}
+}
 
-	// Download Snapshot
-	resp, err := http.Get(snapshotResponse.DownloadURL)
-	if err != nil {
-		redirectToError(w, r, err)
-		return
+// runFinish is the finish workflow. Every step is checkpointed on the round-trip, and steps that were already completed are skipped, so a job interrupted by a restart resumes where it left off.
+func runFinish(ctx context.Context, job *finishJob) {
+	client := job.client
+	rt := job.rt
+
+	// An earlier attempt that could not reopen the Session left it Finalizing, and is undone before starting over
+	if rt.Step < stepFinalizing && rt.pendingCompensations() {
+		if !rollback(ctx, client, rt, job.apply) {
+			job.finish(roundTripActive, "The Session could not be reopened; finishing it again will retry.")
+			return
+		}
 	}
 
-	fileReader := resp.Body
+	// Set Session to Finalizing to boot people
+	if rt.Step < stepFinalizing {
+		_, err := client.Sessions.SetStatus(ctx, rt.SessionID, "Finalizing")
+		// Without the Session there is nothing left to finish
+		if errors.Is(err, studio.ErrSessionNotFound) {
+			job.finish(roundTripFailed, errorMessage(err))
+			return
+		}
+		if err != nil {
+			job.finish(roundTripActive, errorMessage(err))
+			return
+		}
+		job.update(func(rt *RoundTrip) {
+			rt.Step = stepFinalizing
+			rt.compensate(&Compensation{Action: compensateReopenSession, SessionID: rt.SessionID, Name: rt.SessionName})
+		})
+	}
+
+	// Download every Snapshot before the Session is deleted
+	if rt.Step < stepSnapshotsReady {
+		for i, f := range rt.Files {
+			if f.Step < fileSnapshotStarted {
+				job.fileStep(i, fileSnapshotStarted, client.Sessions.StartSnapshot(ctx, rt.SessionID, f.FileSessionID))
+			}
+		}
+
+		for i, f := range rt.Files {
+			if f.Failed() || (f.Step >= fileSnapshotDownloaded && snapshotExists(f.SnapshotPath)) {
+				continue
+			}
+
+			snapshotResponse, err := waitForSnapshot(ctx, client, rt.SessionID, f.FileSessionID)
+			if job.fileStep(i, fileSnapshotStarted, err) {
+				continue
+			}
+
+			path := rt.snapshotPath(f)
+			digest, err := downloadSnapshot(ctx, client, snapshotResponse.DownloadURL, path)
+			if err == nil {
+				job.update(func(rt *RoundTrip) {
+					rt.Files[i].SnapshotPath = path
+					rt.Files[i].SnapshotSize = digest.Size
+					rt.Files[i].SnapshotCRC = digest.CRC
+				})
+			}
+			job.fileStep(i, fileSnapshotDownloaded, err)
+		}
+
+		// Deleting the Session discards the markups of any file without a snapshot, so keep it around for another attempt
+		if failed := rt.failedFiles(); failed > 0 {
+			abortFinish(job, fmt.Sprintf("%d of %d files could not be snapshotted. The Session has been left open so nothing is lost; try finishing it again.", failed, len(rt.Files)))
+			return
+		}
+
+		job.setStep(stepSnapshotsReady)
+	}
 
 	// Delete Session
-	err = sessionDelete(client, sessionID)
-	if err != nil {
-		redirectToError(w, r, err)
+	if rt.Step < stepSessionDeleted {
+		err := client.Sessions.Delete(ctx, rt.SessionID)
+		// A Session that is already gone was deleted by an attempt that stopped before it could checkpoint
+		if err != nil && !errors.Is(err, studio.ErrSessionNotFound) {
+			abortFinish(job, errorMessage(err))
+			return
+		}
+
+		// There is no going back once the Session is gone
+		job.update(func(rt *RoundTrip) {
+			rt.Step = stepSessionDeleted
+			rt.Compensations = nil
+		})
+	}
+
+	// Once the Session is gone each file is checked in on its own so one failure does not hold up the others
+	for i := range rt.Files {
+		checkinSnapshot(ctx, client, job, i)
+	}
+
+	// The snapshots of files that failed are the only copy of their markups, so they are kept for another attempt at the checkin
+	if failed := rt.failedFiles(); failed > 0 {
+		job.finish(roundTripFailed, fmt.Sprintf("%d of %d files could not be checked in. Their snapshots have been kept; finish the session again to retry them.", failed, len(rt.Files)))
 		return
 	}
 
+	job.setStep(stepFinished)
+	rt.removeSnapshots()
+
+	job.finish(roundTripComplete, "")
+}
+
+// abortFinish rolls a finish that failed before the Session was deleted back to an active Session that can be finished again
+func abortFinish(job *finishJob, message string) {
+	ok := rollback(context.Background(), job.client, job.rt, job.apply)
+	if !ok {
+		message += " The Session could not be reopened; finishing it again will retry."
+	}
+
+	// New snapshots are needed as the markups may change before the next attempt
+	job.update(func(rt *RoundTrip) {
+		rt.Step = stepCheckedOut
+		for _, f := range rt.Files {
+			if f.Step > fileCheckedOut {
+				f.Step = fileCheckedOut
+			}
+		}
+	})
+	job.rt.removeSnapshots()
+
+	job.finish(roundTripActive, message)
+}
+
+// checkinSnapshot uploads the snapshot of file i as a new revision of its project file, then flattens and shares it
+func checkinSnapshot(ctx context.Context, client *studio.Client, job *finishJob, i int) {
+	rt := job.rt
+	f := rt.Files[i]
+
 	// Start checkin
-	projectFilesResponse, err := checkinProjectFile(client, projectID, int(fileProjectID))
-	if err != nil {
-		redirectToError(w, r, err)
-		return
+	if f.Step < fileCheckinStarted {
+		projectFilesResponse, err := client.Projects.Checkin(ctx, rt.ProjectID, f.FileProjectID)
+		if err == nil {
+			job.update(func(rt *RoundTrip) {
+				rt.Files[i].UploadURL = projectFilesResponse.UploadUrl
+				rt.Files[i].UploadContentType = projectFilesResponse.UploadContentType
+			})
+		}
+		if job.fileStep(i, fileCheckinStarted, err) {
+			return
+		}
 	}
 
 	// Upload new revision to Aws
-	err = uploadToAWS(projectFilesResponse, fileReader, resp.ContentLength)
-	if err != nil {
-		redirectToError(w, r, err)
-		return
+	if f.Step < fileRevisionUploaded {
+		err := uploadSnapshot(ctx, client, f)
+		if job.fileStep(i, fileRevisionUploaded, err) {
+			return
+		}
+		os.Remove(f.SnapshotPath)
 	}
 
 	// Confirm checkin
-	err = confirmProjectCheckin(client, projectID, int(fileProjectID), "Checkin from Roundtripper")
-	if err != nil {
-		redirectToError(w, r, err)
-		return
+	if f.Step < fileCheckedIn {
+		err := client.Projects.ConfirmCheckin(ctx, rt.ProjectID, f.FileProjectID, "Checkin from Roundtripper")
+		if job.fileStep(i, fileCheckedIn, err) {
+			return
+		}
 	}
 
 	// Kick off job to flatten the file
-	_, err = flattenProjectFile(client, projectID, int(fileProjectID))
-	if err != nil {
-		redirectToError(w, r, err)
-		return
+	if f.Step < fileFlattenStarted {
+		flattenResponse, err := client.Jobs.Flatten(ctx, rt.ProjectID, f.FileProjectID, newFlattenJob())
+		if err == nil {
+			job.update(func(rt *RoundTrip) { rt.Files[i].FlattenJobID = flattenResponse.ID })
+		}
+		if job.fileStep(i, fileFlattenStarted, err) {
+			return
+		}
 	}
 
 	// Generate a share link to the file
-	sharedLinkResponse, err := getSharedLink(client, projectID, int(fileProjectID))
+	if f.Step < fileShared {
+		sharedLinkResponse, err := client.SharedLinks.Create(ctx, rt.ProjectID, &studio.ShareLink{ProjectFileID: f.FileProjectID})
+		if err == nil {
+			job.update(func(rt *RoundTrip) { rt.Files[i].ShareLink = sharedLinkResponse.ShareLink })
+		}
+		job.fileStep(i, fileShared, err)
+	}
+}
+
+// uploadSnapshot sends a downloaded snapshot to the upload URL from the checkin. A snapshot that is no longer what was downloaded is not sent, so a damaged file never becomes the new revision.
+func uploadSnapshot(ctx context.Context, client *studio.Client, f *RoundTripFile) error {
+	snapshot, err := os.Open(f.SnapshotPath)
 	if err != nil {
-		redirectToError(w, r, err)
-		return
+		return err
 	}
+	defer snapshot.Close()
 
-	html, err := Asset("assets/finish.html")
+	digest, err := studio.NewDigest(snapshot)
 	if err != nil {
-		redirectToError(w, r, err)
-		return
+		return err
+	}
+	// Snapshots downloaded before their digest was recorded cannot be checked
+	if f.SnapshotCRC != "" && (digest.Size != f.SnapshotSize || digest.CRC != f.SnapshotCRC) {
+		return fmt.Errorf("The snapshot of %s has changed since it was downloaded and was not checked in. It is kept at %s", f.Name, f.SnapshotPath)
+	}
+	if _, err := snapshot.Seek(0, io.SeekStart); err != nil {
+		return err
 	}
 
-	t, _ := template.New("finishSession").Parse(string(html))
+	return client.Upload(ctx, f.UploadURL, f.UploadContentType, snapshot, digest)
+}
+
+func snapshotExists(path string) bool {
+	if path == "" {
+		return false
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
 
-	finishSessionData := struct {
-		ProjectLink string
-	}{ProjectLink: sharedLinkResponse.ShareLink}
+// waitForSnapshot polls the snapshot status every SnapshotPollInterval until complete or an error. A snapshot that is not ready within SnapshotTimeout fails, so a stuck one does not hold up a worker for good.
+func waitForSnapshot(ctx context.Context, client *studio.Client, sessionID string, fileSessionID int) (*studio.SnapshotResponse, error) {
+	wait, cancel := context.WithTimeout(ctx, env.SnapshotTimeout)
+	defer cancel()
 
-	t.Execute(w, finishSessionData)
+	for {
+		snapshotResponse, err := client.Sessions.SnapshotStatus(wait, sessionID, fileSessionID)
+		if err != nil {
+			return nil, snapshotTimeout(ctx, wait, err)
+		}
+
+		switch snapshotResponse.Status {
+		case "Complete":
+			return snapshotResponse, nil
+		case "Error":
+			return nil, errors.New("Studio could not make the snapshot")
+		}
+
+		select {
+		case <-wait.Done():
+			return nil, snapshotTimeout(ctx, wait, wait.Err())
+		case <-time.After(env.SnapshotPollInterval):
+		}
+	}
+}
+
+// snapshotTimeout explains err when it comes from the snapshot taking longer than SnapshotTimeout rather than from ctx
+func snapshotTimeout(ctx, wait context.Context, err error) error {
+	if ctx.Err() == nil && wait.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("The snapshot was not ready after %s", env.SnapshotTimeout)
+	}
+	return err
+}
+
+// downloadSnapshot saves a snapshot to path so it outlives the Session and the process, returning its digest. The file is written under a temporary name and only renamed once it is complete and as long as advertised.
+func downloadSnapshot(ctx context.Context, client *studio.Client, downloadURL, path string) (*studio.Digest, error) {
+	resp, err := client.Download(ctx, downloadURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+
+	partial := path + ".partial"
+	f, err := os.OpenFile(partial, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	digester := studio.NewDigester()
+	_, err = io.Copy(io.MultiWriter(f, digester), resp.Body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	digest := digester.Digest()
+	if err == nil && resp.ContentLength >= 0 && digest.Size != resp.ContentLength {
+		err = fmt.Errorf("The snapshot download was %d bytes where %d were advertised", digest.Size, resp.ContentLength)
+	}
+	if err != nil {
+		os.Remove(partial)
+		return nil, err
+	}
+
+	return digest, os.Rename(partial, path)
+}
+
+// newFlattenJob flattens every kind of markup on all pages
+func newFlattenJob() *studio.JobFlatten {
+	return &studio.JobFlatten{
+		Recoverable: true,
+		PageRange:   "-1",
+		Options: studio.JobFlattenOptions{
+			Image:             true,
+			Ellipse:           true,
+			Stamp:             true,
+			Snapshot:          true,
+			TextAndCallout:    true,
+			InkAndHighlighter: true,
+			LineAndDimension:  true,
+			MeasureArea:       true,
+			Polyline:          true,
+			PolygonAndCloud:   true,
+			Rectangle:         true,
+			TextMarkups:       true,
+			Group:             true,
+			FileAttachment:    true,
+			Flags:             true,
+			Notes:             true,
+			FormFields:        true,
+		},
+	}
 }
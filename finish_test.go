@@ -0,0 +1,127 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testFinishEnv points env at a fresh BoltDB and at jobs, putting the previous env back when the test is done
+func testFinishEnv(t *testing.T, jobs *jobQueue) {
+	store := &BoltDBStore{Path: filepath.Join(t.TempDir(), "my.db")}
+	if err := store.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	previous := env
+	env = &environment{DataStore: store, Jobs: jobs}
+	t.Cleanup(func() {
+		env = previous
+		store.Close()
+	})
+}
+
+// TestStartFinishRunning has a stale copy of a round-trip finished while a job for it is still running. The record of the running job must be left alone.
+func TestStartFinishRunning(t *testing.T) {
+	// Without workers the reserved job never finishes
+	testFinishEnv(t, &jobQueue{queue: make(chan *finishJob, 1), jobs: map[string]*finishJob{}})
+
+	running := &RoundTrip{ID: "running", State: roundTripFinishing, Step: stepSessionDeleted, Files: []*RoundTripFile{{Name: "a.pdf"}}}
+	if err := saveRoundTrip(running); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.Jobs.Enqueue(newFinishJob(running, nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	stale := &RoundTrip{ID: "running", State: roundTripActive, Step: stepCheckedOut, Error: "Earlier failure", Files: []*RoundTripFile{{Name: "a.pdf", Error: "Earlier failure"}}}
+	if err := startFinish(stale, nil); !errors.Is(err, errAlreadyFinishing) {
+		t.Fatalf("startFinish returned %v, want errAlreadyFinishing", err)
+	}
+
+	got, err := env.DataStore.GetRoundTrip(context.Background(), "running")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.State != roundTripFinishing || got.Step != stepSessionDeleted {
+		t.Errorf("The running round-trip was overwritten with %s at %s", got.State, got.Step)
+	}
+}
+
+// TestStartFinishQueueFull checks that a round-trip whose job cannot be queued is put back as it was
+func TestStartFinishQueueFull(t *testing.T) {
+	// Nothing takes jobs off an unbuffered queue without workers
+	testFinishEnv(t, &jobQueue{queue: make(chan *finishJob), retain: time.Hour, jobs: map[string]*finishJob{}})
+
+	rt := &RoundTrip{ID: "full", State: roundTripActive, Step: stepCheckedOut, Error: "Earlier failure", Files: []*RoundTripFile{{Name: "a.pdf", Error: "Earlier failure"}}}
+	if err := startFinish(rt, nil); !errors.Is(err, errQueueFull) {
+		t.Fatalf("startFinish returned %v, want errQueueFull", err)
+	}
+
+	got, err := env.DataStore.GetRoundTrip(context.Background(), "full")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.State != roundTripActive || got.Error != "Earlier failure" || got.Files[0].Error != "Earlier failure" {
+		t.Errorf("The round-trip was left %s with error %q", got.State, got.Error)
+	}
+	if _, ok := env.Jobs.Get("full", ""); ok {
+		t.Error("The job that could not be queued is still reserved")
+	}
+}
+
+// TestStartFinishLeased checks that a round-trip another instance holds the lease of is neither finished nor changed
+func TestStartFinishLeased(t *testing.T) {
+	testFinishEnv(t, &jobQueue{queue: make(chan *finishJob, 1), jobs: map[string]*finishJob{}})
+
+	rt := &RoundTrip{ID: "leased", State: roundTripActive, Step: stepCheckedOut}
+	if err := saveRoundTrip(rt); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.DataStore.AcquireLease(context.Background(), "roundtrip:leased", "other", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := startFinish(rt, nil); !errors.Is(err, errRoundTripBusy) {
+		t.Fatalf("startFinish returned %v, want errRoundTripBusy", err)
+	}
+	if _, ok := env.Jobs.Get("leased", ""); ok {
+		t.Error("A job was reserved for the leased round-trip")
+	}
+	got, err := env.DataStore.GetRoundTrip(context.Background(), "leased")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.State != roundTripActive {
+		t.Errorf("The leased round-trip was left %s", got.State)
+	}
+}
+
+// TestResumeLeased has another instance still working on a round-trip left finishing. Resuming at startup must leave it alone.
+func TestResumeLeased(t *testing.T) {
+	testFinishEnv(t, &jobQueue{queue: make(chan *finishJob, 1), jobs: map[string]*finishJob{}})
+
+	rt := &RoundTrip{ID: "leased", UserID: "user", State: roundTripFinishing, Step: stepSnapshotsReady}
+	if err := saveRoundTrip(rt); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.DataStore.AcquireLease(context.Background(), "roundtrip:leased", "other", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	resumeRoundTrips()
+
+	if _, ok := env.Jobs.Get("leased", "user"); ok {
+		t.Error("The round-trip leased by another instance was resumed")
+	}
+	// The lease is still the other instance's
+	if acquired, err := env.DataStore.AcquireLease(context.Background(), "roundtrip:leased", "other", time.Minute); err != nil || !acquired {
+		t.Errorf("The other instance lost its lease: %v %v", acquired, err)
+	}
+}
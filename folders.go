@@ -0,0 +1,88 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"bluebeam/gosessionroundtripper/studio"
+)
+
+type folderOption struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Depth int    `json:"depth"`
+}
+
+// foldersHandler returns the folders of a project as a flattened tree for the folder picker on the home page
+func foldersHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projectID := r.URL.Query().Get("project")
+	if projectID == "" {
+		http.Error(w, "project is required", http.StatusBadRequest)
+		return
+	}
+
+	client, err := getStudioClient(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	tree, err := client.Projects.FolderTree(ctx, projectID)
+	if err != nil {
+		http.Error(w, errorMessage(err), http.StatusBadGateway)
+		return
+	}
+
+	options := []folderOption{}
+	tree.Walk(func(n *studio.FolderNode, depth int) {
+		options = append(options, folderOption{ID: n.ID, Name: n.Name, Depth: depth})
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(options)
+}
+
+type fileOption struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	CheckedOut bool   `json:"checkedOut"`
+}
+
+// filesHandler returns the files in a project folder for picking existing files on the home page
+func filesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projectID := r.URL.Query().Get("project")
+	if projectID == "" {
+		http.Error(w, "project is required", http.StatusBadRequest)
+		return
+	}
+	folderID, _ := strconv.Atoi(r.URL.Query().Get("folder"))
+
+	client, err := getStudioClient(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	files, err := client.Projects.FolderFiles(ctx, projectID, folderID)
+	if err != nil {
+		http.Error(w, errorMessage(err), http.StatusBadGateway)
+		return
+	}
+
+	options := []fileOption{}
+	for _, f := range files {
+		options = append(options, fileOption{ID: f.ID, Name: f.Name, CheckedOut: f.CheckedOut})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(options)
+}
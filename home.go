@@ -5,10 +5,13 @@
 package main
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
 	"html/template"
 	"net/http"
+	"strconv"
+	"strings"
+
+	"bluebeam/gosessionroundtripper/studio"
 )
 
 func homePage(w http.ResponseWriter, r *http.Request) {
@@ -27,33 +30,94 @@ func homePage(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	u := ctx.Value("user").(user)
 
-	client := env.OAuthConfig.Client(ctx, u.Token)
-
-	req, err := http.NewRequest("GET", "https://studioapi.bluebeam.com/publicapi/v1/projects", nil)
-	resp, err := client.Do(req)
+	client, err := getStudioClient(ctx)
 	if err != nil {
 		redirectToError(w, r, err)
 		return
 	}
 
-	if ok, err := checkHTTPResponse(resp); !ok {
-		redirectToError(w, r, err)
-		return
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
 	}
 
-	projects := ProjectsResponse{}
-	err = json.NewDecoder(resp.Body).Decode(&projects)
+	var projects []*studio.Project
+	var totalCount int
+
+	if query == "" {
+		// Without a search only the requested page is needed
+		resp, err := client.Projects.List(ctx, &studio.ListOptions{Skip: (page - 1) * projectsPerPage, Take: projectsPerPage})
+		if err != nil {
+			redirectToError(w, r, err)
+			return
+		}
+		projects, totalCount = resp.Projects, resp.TotalCount
+	} else {
+		projects, totalCount, err = searchProjects(ctx, client, query, page)
+		if err != nil {
+			redirectToError(w, r, err)
+			return
+		}
+	}
+
+	prefs, err := env.DataStore.GetUserPrefs(ctx, u.UserID)
 	if err != nil {
 		redirectToError(w, r, err)
 		return
 	}
 
-	fmt.Println(projects)
-
 	homeData := struct {
-		UserID   string
-		Projects []*Project
-	}{u.UserID, projects.Projects}
+		UserID     string
+		Projects   []*studio.Project
+		Query      string
+		Page       int
+		PageCount  int
+		TotalCount int
+		PrevPage   int
+		NextPage   int
+		CSRFToken  string
+		Prefs      *UserPrefs
+	}{UserID: u.UserID, Projects: projects, Query: query, Page: page, TotalCount: totalCount, CSRFToken: csrfToken(r), Prefs: prefs}
+
+	homeData.PageCount = (totalCount + projectsPerPage - 1) / projectsPerPage
+	if page > 1 {
+		homeData.PrevPage = page - 1
+	}
+	if page < homeData.PageCount {
+		homeData.NextPage = page + 1
+	}
 
 	t.Execute(w, homeData)
 }
+
+// projectsPerPage is the number of Projects shown in the drop-down at once
+const projectsPerPage = 50
+
+// searchProjects walks all of the user's Projects and returns the requested page of those whose name contains query, along with the total number of matches
+func searchProjects(ctx context.Context, client *studio.Client, query string, page int) ([]*studio.Project, int, error) {
+	query = strings.ToLower(query)
+	first := (page - 1) * projectsPerPage
+
+	var matches []*studio.Project
+	total := 0
+
+	it := client.Projects.Iterate(0)
+	for it.Next(ctx) {
+		p := it.Project()
+		if !strings.Contains(strings.ToLower(p.Name), query) {
+			continue
+		}
+
+		if total >= first && total < first+projectsPerPage {
+			matches = append(matches, p)
+		}
+		total++
+	}
+
+	if err := it.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return matches, total, nil
+}
@@ -0,0 +1,233 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"bluebeam/gosessionroundtripper/studio"
+)
+
+// finishJob is a finish workflow running in the background. The round-trip is only changed through update so that progress can be read while the job runs.
+type finishJob struct {
+	client *studio.Client
+	// lease keeps other instances from resuming the round-trip while the job runs
+	lease *roundTripLease
+
+	mu      sync.Mutex
+	rt      *RoundTrip
+	done    bool
+	message string
+	changed chan struct{}
+}
+
+// jobProgress is a point in time copy of a finish job, sent to the browser as it changes
+type jobProgress struct {
+	ID       string             `json:"id"`
+	Step     string             `json:"step"`
+	Done     bool               `json:"done"`
+	Message  string             `json:"message"`
+	Files    []fileProgress     `json:"files"`
+	Rollback []rollbackProgress `json:"rollback"`
+}
+
+type rollbackProgress struct {
+	Description string `json:"description"`
+	Done        bool   `json:"done"`
+	Error       string `json:"error"`
+}
+
+type fileProgress struct {
+	Name      string `json:"name"`
+	Step      string `json:"step"`
+	Error     string `json:"error"`
+	ShareLink string `json:"shareLink"`
+	Failed    bool   `json:"failed"`
+}
+
+func newFinishJob(rt *RoundTrip, client *studio.Client) *finishJob {
+	return &finishJob{
+		rt:      rt,
+		client:  client,
+		changed: make(chan struct{}),
+	}
+}
+
+// update applies fn to the round-trip, checkpoints it and wakes up everyone watching the job
+func (j *finishJob) update(fn func(rt *RoundTrip)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	fn(j.rt)
+	saveRoundTrip(j.rt)
+
+	close(j.changed)
+	j.changed = make(chan struct{})
+}
+
+// apply is the update of a rollback run by the job
+func (j *finishJob) apply(fn func()) {
+	j.update(func(*RoundTrip) { fn() })
+}
+
+// setStep records the Session level step the round-trip has reached
+func (j *finishJob) setStep(step roundTripStep) {
+	fmt.Printf("Finish %s: %s\n", j.rt.ID, step)
+	j.update(func(rt *RoundTrip) { rt.Step = step })
+}
+
+// fileStep records the step file i has reached, or the error that stopped it. It reports whether err is not nil.
+func (j *finishJob) fileStep(i int, step fileStep, err error) bool {
+	j.update(func(rt *RoundTrip) {
+		f := rt.Files[i]
+		if err != nil {
+			fmt.Printf("Finish %s: %s (%d): %s: %v\n", rt.ID, f.Name, f.FileProjectID, step, err)
+			f.Error = errorMessage(err)
+			return
+		}
+		f.Step = step
+	})
+	return err != nil
+}
+
+// finish marks the job as done, leaving the round-trip in state
+func (j *finishJob) finish(state, message string) {
+	fmt.Printf("Finish %s: %s %s\n", j.rt.ID, state, message)
+	j.update(func(rt *RoundTrip) {
+		rt.State = state
+		rt.Error = message
+		j.done = true
+		j.message = message
+	})
+}
+
+// Done reports whether the job has finished running
+func (j *finishJob) Done() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.done
+}
+
+// Progress returns a copy of the job state along with a channel that is closed the next time it changes
+func (j *finishJob) Progress() (jobProgress, <-chan struct{}) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return roundTripProgress(j.rt, j.done), j.changed
+}
+
+// roundTripProgress describes a round-trip for the finish status page
+func roundTripProgress(rt *RoundTrip, done bool) jobProgress {
+	p := jobProgress{ID: rt.ID, Step: rt.Step.String(), Done: done, Message: rt.Error}
+	if !done {
+		p.Step = "Finishing: " + p.Step
+	}
+	for _, f := range rt.Files {
+		p.Files = append(p.Files, fileProgress{Name: f.Name, Step: f.Step.String(), Error: f.Error, ShareLink: f.ShareLink, Failed: f.Failed()})
+	}
+	for _, c := range rt.Compensations {
+		if c.Done || c.Failed() {
+			p.Rollback = append(p.Rollback, rollbackProgress{Description: c.String(), Done: c.Done, Error: c.Error})
+		}
+	}
+	return p
+}
+
+// jobQueue runs finish jobs on a fixed number of workers and keeps track of them until they expire
+type jobQueue struct {
+	queue  chan *finishJob
+	retain time.Duration
+
+	mu   sync.Mutex
+	jobs map[string]*finishJob
+}
+
+func newJobQueue(workers int, retain time.Duration) *jobQueue {
+	q := &jobQueue{
+		queue:  make(chan *finishJob, 100),
+		retain: retain,
+		jobs:   map[string]*finishJob{},
+	}
+
+	for i := 0; i < workers; i++ {
+		go q.work()
+	}
+
+	return q
+}
+
+// Errors of Enqueue
+var (
+	errAlreadyFinishing = errors.New("This session is already being finished")
+	errQueueFull        = errors.New("Too many sessions are being finished, try again later")
+)
+
+// Enqueue schedules a job. It fails rather than blocks when the queue is full.
+func (q *jobQueue) Enqueue(job *finishJob) error {
+	if err := q.reserve(job); err != nil {
+		return err
+	}
+	return q.submit(job)
+}
+
+// reserve records job as the one for its round-trip, unless another job for it has yet to finish
+func (q *jobQueue) reserve(job *finishJob) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if running, ok := q.jobs[job.rt.ID]; ok && !running.Done() {
+		return errAlreadyFinishing
+	}
+	q.jobs[job.rt.ID] = job
+	return nil
+}
+
+// submit hands a reserved job to the workers, giving up its reservation when the queue is full
+func (q *jobQueue) submit(job *finishJob) error {
+	select {
+	case q.queue <- job:
+		return nil
+	default:
+		q.remove(job)
+		return errQueueFull
+	}
+}
+
+// Get returns the job for the round-trip with the given id if it belongs to userID
+func (q *jobQueue) Get(id, userID string) (*finishJob, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[id]
+	if !ok || job.rt.UserID != userID {
+		return nil, false
+	}
+	return job, true
+}
+
+// remove forgets job, unless a newer job for the same round-trip has taken its place
+func (q *jobQueue) remove(job *finishJob) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.jobs[job.rt.ID] == job {
+		delete(q.jobs, job.rt.ID)
+	}
+}
+
+func (q *jobQueue) work() {
+	for job := range q.queue {
+		runFinish(context.Background(), job)
+		job.lease.Release()
+
+		job := job
+		time.AfterFunc(q.retain, func() { q.remove(job) })
+	}
+}
@@ -6,43 +6,47 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"html/template"
-	"math/rand"
 	"net/http"
-	"strconv"
+	"net/url"
+	"strings"
 	"time"
 
+	"bluebeam/gosessionroundtripper/studio"
+
 	"golang.org/x/oauth2"
 )
 
 type user struct {
-	Token  *oauth2.Token
-	UserID string
+	Token     *oauth2.Token
+	UserID    string
+	SessionID string
 }
 
 func authHandler(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		userID := ""
-		cookie, err := r.Cookie("userId")
-		if err == nil {
-			userID = cookie.Value
-		}
-
-		if userID == "" {
-			http.Redirect(w, r, "/login", http.StatusFound)
+		// The user comes from the login session named by the signed session cookie, never from a value the browser can choose
+		session, err := currentLoginSession(r)
+		if err != nil {
+			redirectToLogin(w, r)
 			return
 		}
+		userID := session.UserID
 
-		token, err := env.DataStore.GetToken(userID)
+		token, err := env.DataStore.GetToken(r.Context(), userID)
 		if err != nil {
-			http.Redirect(w, r, "/login", http.StatusFound)
+			redirectToLogin(w, r)
 			return
 		}
 
-		// This will possibly do a token refresh
-		tokenSource := env.OAuthConfig.TokenSource(r.Context(), token)
+		// This will possibly do a token refresh, shared with any other request refreshing the same token
+		tokenSource := env.OAuthConfig.TokenSource(r.Context(), userID, token)
 		newToken, err := tokenSource.Token()
 
 		if err != nil {
@@ -52,7 +56,7 @@ func authHandler(h http.Handler) http.Handler {
 		}
 
 		// Store the token in the context for use by a page handler
-		u := user{Token: newToken, UserID: userID}
+		u := user{Token: newToken, UserID: userID, SessionID: session.ID}
 
 		ctx := context.WithValue(r.Context(), "user", u)
 		r = r.WithContext(ctx)
@@ -61,11 +65,27 @@ func authHandler(h http.Handler) http.Handler {
 	})
 }
 
-func getOAuthClient(ctx context.Context) *http.Client {
+// redirectToLogin sends the user to log in, coming back to the page they asked for afterwards. Only pages can be returned to; a form post has to be submitted again.
+func redirectToLogin(w http.ResponseWriter, r *http.Request) {
+	login := "/login"
+	if r.Method == "GET" && r.URL.Path != "/" {
+		login += "?returnTo=" + url.QueryEscape(r.URL.RequestURI())
+	}
+
+	http.Redirect(w, r, login, http.StatusFound)
+}
+
+// getStudioClient retrieves a Studio API client acting on behalf of the authenticated user
+func getStudioClient(ctx context.Context) (*studio.Client, error) {
 	u := ctx.Value("user").(user)
 	token := u.Token
 
-	return env.OAuthConfig.Client(ctx, token)
+	return newStudioClient(env.OAuthConfig.TokenSource(ctx, u.UserID, token))
+}
+
+// newStudioClient creates a Studio API client whose calls go through the shared circuit breaker and are retried as env says
+func newStudioClient(ts oauth2.TokenSource) (*studio.Client, error) {
+	return studio.NewClient(env.StudioURL, ts, studio.WithBreaker(env.Breaker), studio.WithRetryPolicy(env.RetryPolicy))
 }
 
 func loginPage(w http.ResponseWriter, r *http.Request) {
@@ -77,27 +97,50 @@ func loginPage(w http.ResponseWriter, r *http.Request) {
 
 	t, _ := template.New("login").Parse(string(html))
 	loginData := struct {
-	}{}
+		ReturnTo string
+	}{ReturnTo: safeReturnTo(r.URL.Query().Get("returnTo"))}
 
-	state := strconv.Itoa(rand.New(rand.NewSource(time.Now().UnixNano())).Int())
+	t.Execute(w, loginData)
+}
 
-	cookie := &http.Cookie{Name: "state", Value: state}
-	http.SetCookie(w, cookie)
+// stateCookie holds the pending authorization between oauthRedirect and oauthCallback
+const stateCookie = "oauthState"
 
-	t.Execute(w, loginData)
+// stateLifetime is how long the user has to complete the authorization
+const stateLifetime = 10 * time.Minute
+
+// oauthState is the pending authorization kept in the signed and encrypted state cookie
+type oauthState struct {
+	State    string `json:"state"`
+	Verifier string `json:"verifier,omitempty"`
+	ReturnTo string `json:"returnTo,omitempty"`
 }
 
 func oauthRedirect(w http.ResponseWriter, r *http.Request) {
+	id := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		redirectToError(w, r, err)
+		return
+	}
+
+	pending := oauthState{
+		State:    base64.RawURLEncoding.EncodeToString(id),
+		Verifier: env.OAuthConfig.NewVerifier(),
+		ReturnTo: safeReturnTo(r.FormValue("returnTo")),
+	}
 
-	cookie, err := r.Cookie("state")
+	data, err := json.Marshal(pending)
 	if err != nil {
 		redirectToError(w, r, err)
 		return
 	}
 
-	url := env.OAuthConfig.AuthCodeURL(cookie.Value, oauth2.AccessTypeOnline)
+	if err := env.Cookies.SetCookie(w, stateCookie, string(data), stateLifetime); err != nil {
+		redirectToError(w, r, err)
+		return
+	}
 
-	http.Redirect(w, r, url, http.StatusFound)
+	http.Redirect(w, r, env.OAuthConfig.AuthCodeURL(pending.State, pending.Verifier), http.StatusFound)
 }
 
 func oauthCallback(w http.ResponseWriter, r *http.Request) {
@@ -113,20 +156,27 @@ func oauthCallback(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate state
-	cookie, err := r.Cookie("state")
+	// Validate state. A cookie that was tampered with or is older than stateLifetime is rejected, and it can only be used once.
+	value, err := env.Cookies.Value(r, stateCookie, stateLifetime)
 	if err != nil {
+		redirectToError(w, r, errors.New("Authorization Error: the login expired, please try again"))
+		return
+	}
+	env.Cookies.ClearCookie(w, stateCookie)
+
+	pending := oauthState{}
+	if err := json.Unmarshal([]byte(value), &pending); err != nil {
 		redirectToError(w, r, err)
 		return
 	}
 
-	if state != cookie.Value {
+	if subtle.ConstantTimeCompare([]byte(state), []byte(pending.State)) != 1 {
 		redirectToError(w, r, errors.New("Authorization Error"))
 		return
 	}
 
 	// Exchange Token
-	token, err := env.OAuthConfig.Exchange(ctx, code)
+	token, err := env.OAuthConfig.Exchange(ctx, code, pending.Verifier)
 
 	if err != nil {
 		redirectToError(w, r, err)
@@ -134,13 +184,58 @@ func oauthCallback(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get the username out of the token
-	userName := token.Extra("userName").(string)
+	userName, err := tokenUserName(token)
+	if err != nil {
+		redirectToError(w, r, err)
+		return
+	}
+
+	// Send the session cookie back to the client
+	if err := startLoginSession(ctx, w, userName); err != nil {
+		redirectToError(w, r, err)
+		return
+	}
+
+	// Drop the unprotected cookies set by earlier versions
+	env.Cookies.ClearCookie(w, "userId")
+	env.Cookies.ClearCookie(w, "state")
+
+	returnTo := pending.ReturnTo
+	if returnTo == "" {
+		returnTo = "/"
+	}
+
+	http.Redirect(w, r, returnTo, http.StatusFound)
+}
+
+// logoutPage signs the user out and revokes their grant. The stored token is shared by all of the user's logins, so every browser they are logged in with is signed out.
+func logoutPage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	u := r.Context().Value("user").(user)
+
+	if err := revokeUserGrant(r.Context(), u.UserID); err != nil {
+		fmt.Printf("Logging out %s: %v\n", u.UserID, err)
+	}
+
+	env.Cookies.ClearCookie(w, sessionCookie)
+
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
 
-	// Send a cookie back to the client
-	expiration := time.Now().Add(30 * 24 * time.Hour) // 1 month
-	cookie = &http.Cookie{Name: "userId", Value: userName, Expires: expiration}
+// safeReturnTo only lets a post-login return URL through when it is a path on this site, so the login cannot be used to redirect to another site
+func safeReturnTo(returnTo string) string {
+	if !strings.HasPrefix(returnTo, "/") || strings.HasPrefix(returnTo, "//") || strings.HasPrefix(returnTo, "/\\") {
+		return ""
+	}
 
-	http.SetCookie(w, cookie)
+	u, err := url.Parse(returnTo)
+	if err != nil || u.Scheme != "" || u.Host != "" {
+		return ""
+	}
 
-	http.Redirect(w, r, "/", http.StatusFound)
+	return u.RequestURI()
 }
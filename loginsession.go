@@ -0,0 +1,99 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// sessionCookie holds the id of the user's login session. It is not to be confused with a Studio Session.
+const sessionCookie = "session"
+
+// loginSessionLifetime is how long a login lasts before the user has to authorize the app again
+const loginSessionLifetime = 30 * 24 * time.Hour // 1 month
+
+// LoginSession ties an opaque id held in the session cookie to the user it was issued to. The cookie never carries the user id itself.
+type LoginSession struct {
+	ID      string    `json:"id"`
+	UserID  string    `json:"userId"`
+	Created time.Time `json:"created"`
+	Expires time.Time `json:"expires"`
+}
+
+// startLoginSession records a new login session for the user and sends its id back in the session cookie
+func startLoginSession(ctx context.Context, w http.ResponseWriter, userID string) error {
+	id := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	s := &LoginSession{ID: hex.EncodeToString(id), UserID: userID, Created: now, Expires: now.Add(loginSessionLifetime)}
+	if err := env.DataStore.StoreLoginSession(ctx, s); err != nil {
+		return err
+	}
+
+	return env.Cookies.SetCookie(w, sessionCookie, s.ID, loginSessionLifetime)
+}
+
+// currentLoginSession returns the login session named by the request's session cookie. A cookie that was tampered with, or names an unknown or expired session, is rejected.
+func currentLoginSession(r *http.Request) (*LoginSession, error) {
+	id, err := env.Cookies.Value(r, sessionCookie, loginSessionLifetime)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := env.DataStore.GetLoginSession(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	if time.Now().After(s.Expires) {
+		return nil, errors.New("Login session expired")
+	}
+
+	return s, nil
+}
+
+// revokeError is an error of revokeUserGrant. Deleted tells whether the stored token and login sessions were removed, leaving only the grant that the auth server did not revoke.
+type revokeError struct {
+	Deleted bool
+	Err     error
+}
+
+func (e *revokeError) Error() string {
+	if e.Deleted {
+		return "The grant could not be revoked with the auth server: " + e.Err.Error()
+	}
+	return "The stored token and login sessions could not be deleted: " + e.Err.Error()
+}
+
+func (e *revokeError) Unwrap() error {
+	return e.Err
+}
+
+// revokeUserGrant revokes a user's grant with the auth server and forgets their token and login sessions. The stored data is removed even when the auth server cannot be reached. Errors are a *revokeError telling which of the two failed.
+func revokeUserGrant(ctx context.Context, userID string) error {
+	var revokeErr error
+	if token, err := env.DataStore.GetToken(ctx, userID); err == nil {
+		if err := env.OAuthConfig.Revoke(ctx, token); err != nil {
+			revokeErr = &revokeError{Deleted: true, Err: err}
+		}
+	}
+
+	if err := env.DataStore.DeleteToken(ctx, userID); err != nil {
+		return &revokeError{Err: err}
+	}
+	if err := env.DataStore.DeleteUserLoginSessions(ctx, userID); err != nil {
+		return &revokeError{Err: err}
+	}
+
+	return revokeErr
+}
@@ -5,53 +5,170 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"bluebeam/gosessionroundtripper/studio"
+	"bluebeam/gosessionroundtripper/studio/studiotest"
 
 	"golang.org/x/oauth2"
 )
 
 var env *environment
 
+// fakeStudioPath and devAuthPath are where the fake Studio and the development auth server are served when they are turned on
+const (
+	fakeStudioPath = "/fakestudio"
+	devAuthPath    = "/devauth"
+)
+
+// defaultAuthURL is the Studio auth server
+const defaultAuthURL = "https://authserver.bluebeam.com"
+
 // Root singleton to store our application state
 type environment struct {
 	OAuthConfig *StudioConfig
 	DataStore   DataStore
+	StudioURL   string
+	SnapshotDir string
+	Jobs        *jobQueue
+	Cookies     *cookieCodec
+	Admins      []string
+	// Breaker is shared by all the Studio clients so an outage seen by one request spares the others the wait
+	Breaker *studio.Breaker
+	// RetryPolicy is how the Studio clients retry failed calls
+	RetryPolicy studio.RetryPolicy
+	// SnapshotPollInterval is how often a snapshot in progress is checked on, for up to SnapshotTimeout
+	SnapshotPollInterval time.Duration
+	SnapshotTimeout      time.Duration
 }
 
 func main() {
-	conf, err := initOauth()
+	config, err := loadConfig()
 	if err != nil {
 		log.Fatal(err)
 		return
 	}
 
-	// BoltDB is used as a simple Database to store OAuth tokens
-	dataStore := &BoltDBStore{}
-	dataStore.New()
+	conf := initOauth(config)
 
-	env = &environment{OAuthConfig: conf, DataStore: dataStore}
+	// Tokens are encrypted at rest once master keys are configured
+	tokens, err := newTokenCipher(config.TokenKeys)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	if tokens == nil {
+		fmt.Println("No token keys are configured, OAuth tokens are stored unencrypted")
+	}
 
-	// These pages are protected by authentication
-	http.Handle("/", authHandler(http.HandlerFunc(homePage)))
-	http.Handle("/create", authHandler(http.HandlerFunc(createPage)))
-	http.Handle("/finish", authHandler(http.HandlerFunc(finishPage)))
+	dataStore, err := newDataStore(config, tokens)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	if err := dataStore.Open(context.Background()); err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	// Maintenance commands run against the database instead of starting the server
+	if len(os.Args) > 1 {
+		err := runCommand(context.Background(), config, tokens, dataStore, os.Args[1:])
+		dataStore.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Cookies are only sent over HTTPS when the app is served over HTTPS
+	cookies, err := newCookieCodec(cookieSecrets(config), strings.HasPrefix(config.URL, "https://"))
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	// Finish jobs run in the background and are kept for an hour so their progress can be viewed
+	jobs := newJobQueue(4, time.Hour)
+
+	env = &environment{OAuthConfig: conf, DataStore: dataStore, StudioURL: config.APIURL, SnapshotDir: config.SnapshotDir, Jobs: jobs, Cookies: cookies, Admins: config.Admins, Breaker: studio.NewBreaker(8, 30*time.Second), RetryPolicy: studio.DefaultRetryPolicy, SnapshotPollInterval: 5 * time.Second, SnapshotTimeout: time.Duration(config.SnapshotTimeout) * time.Minute}
+
+	// Carry on with any round-trips interrupted by the last shutdown
+	resumeRoundTrips()
+
+	mux := routes()
+
+	// The development auth server and the fake Studio stand in for the real ones in development and demos, on the app's own server
+	var auth *studiotest.AuthServer
+	if config.DevAuth {
+		fmt.Println("**********************************************************************")
+		fmt.Println("WARNING: devAuth is on. Anyone can sign in as any user at " + config.AuthURL)
+		fmt.Println("Never turn it on for a server that real users can reach.")
+		fmt.Println("**********************************************************************")
+		auth = studiotest.NewAuthServer(config.ClientID, config.ClientSecret)
+		mux.Handle(devAuthPath+"/", http.StripPrefix(devAuthPath, auth))
+	}
+
+	if config.FakeStudio {
+		fake := studiotest.NewServer()
+		fake.BaseURL = config.URL + fakeStudioPath
+		fake.SnapshotDelay = 3 * time.Second
+		fake.Seed()
+		if auth != nil {
+			fake.Authenticate = auth.Valid
+		}
+		mux.Handle(fakeStudioPath+"/", http.StripPrefix(fakeStudioPath, fake))
+	}
+
+	http.ListenAndServe(":5000", mux)
+}
+
+// routes registers the pages of the app
+func routes() *http.ServeMux {
+	mux := http.NewServeMux()
+
+	// These pages are protected by authentication, and their forms by a CSRF token
+	mux.Handle("/", authHandler(csrfHandler(http.HandlerFunc(homePage))))
+	mux.Handle("/folders", authHandler(csrfHandler(http.HandlerFunc(foldersHandler))))
+	mux.Handle("/files", authHandler(csrfHandler(http.HandlerFunc(filesHandler))))
+	mux.Handle("/create", authHandler(csrfHandler(http.HandlerFunc(createPage))))
+	mux.Handle("/roundtrips", authHandler(csrfHandler(http.HandlerFunc(roundTripsPage))))
+	mux.Handle("/adopt", authHandler(csrfHandler(http.HandlerFunc(adoptPage))))
+	mux.Handle("/adopt/finish", authHandler(csrfHandler(http.HandlerFunc(adoptFinishPage))))
+	mux.Handle("/finish", authHandler(csrfHandler(http.HandlerFunc(finishPage))))
+	mux.Handle("/finish/status", authHandler(csrfHandler(http.HandlerFunc(finishStatusPage))))
+	mux.Handle("/finish/events", authHandler(csrfHandler(http.HandlerFunc(finishEventsHandler))))
+	mux.Handle("/logout", authHandler(csrfHandler(http.HandlerFunc(logoutPage))))
+
+	// These pages are only open to the admins from the config
+	mux.Handle("/admin", authHandler(csrfHandler(adminHandler(http.HandlerFunc(adminPage)))))
+	mux.Handle("/admin/revoke", authHandler(csrfHandler(adminHandler(http.HandlerFunc(adminRevokeHandler)))))
+	mux.Handle("/admin/delete", authHandler(csrfHandler(adminHandler(http.HandlerFunc(adminDeleteHandler)))))
 
 	// The pages are all part of the OAuth flow
-	http.HandleFunc("/login", loginPage)
-	http.HandleFunc("/oauth", oauthRedirect)
-	http.HandleFunc("/callback", oauthCallback)
+	mux.HandleFunc("/login", loginPage)
+	mux.HandleFunc("/oauth", oauthRedirect)
+	mux.HandleFunc("/callback", oauthCallback)
 
-	http.HandleFunc("/error", errorPage)
+	mux.HandleFunc("/error", errorPage)
 
 	// CSS and JS are found in assets.go
-	http.HandleFunc("/style.css", cssHandler)
-	http.HandleFunc("/script.js", scriptHandler)
+	mux.HandleFunc("/style.css", cssHandler)
+	mux.HandleFunc("/script.js", scriptHandler)
 
-	http.ListenAndServe(":5000", nil)
+	return mux
 }
 
 func cssHandler(w http.ResponseWriter, r *http.Request) {
@@ -66,15 +183,38 @@ func scriptHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(script)
 }
 
-func initOauth() (*StudioConfig, error) {
-	// Do not be alarmed by this call. This is simply because the Studio Auth server expects the clientId and secretId to be in query parameters rather than the Authorization header
-	oauth2.RegisterBrokenAuthHeaderProvider("https://authserver.bluebeam.com/auth/token")
+type config struct {
+	ClientID     string `json:"clientId"`
+	ClientSecret string `json:"clientSecret"`
+	URL          string `json:"url"`
+	APIURL       string `json:"apiUrl"`
+	SnapshotDir  string `json:"snapshotDir"`
+	// SnapshotTimeout is how many minutes a snapshot may take before the finish gives up on it, 10 when not set
+	SnapshotTimeout int `json:"snapshotTimeout"`
+	// CookieKeys protect the session cookie, newest first. The client secret is used when there are none.
+	CookieKeys []string `json:"cookieKeys"`
+	// PKCE turns on Proof Key for Code Exchange in the login flow
+	PKCE bool `json:"pkce"`
+	// RevokeURL is the token revocation endpoint used on logout
+	RevokeURL string `json:"revokeUrl"`
+	// Admins are the users allowed to revoke the grants of other users
+	Admins []string `json:"admins"`
+	// TokenKeys are the master keys that encrypt the stored tokens, as id:base64 key, newest first
+	TokenKeys []string `json:"tokenKeys"`
+	// Database is the DataStore backend: bolt (the default), postgres, sqlite or redis
+	Database string `json:"database"`
+	// DatabaseURL is the connection string for postgres and redis, or the file for bolt and sqlite
+	DatabaseURL string `json:"databaseUrl"`
+	// FakeStudio serves an in-memory fake of the Studio API from the app itself and uses it instead of Studio, for demos without a Studio account
+	FakeStudio bool `json:"fakeStudio"`
+	// AuthURL is the root of the OAuth authorization server, the Studio auth server when empty
+	AuthURL string `json:"authUrl"`
+	// DevAuth serves a development auth server from the app itself and logs in through it, so no Studio credentials are needed
+	DevAuth bool `json:"devAuth"`
+}
 
-	config := struct {
-		ClientID     string `json:"clientId"`
-		ClientSecret string `json:"clientSecret"`
-		URL          string `json:"url"`
-	}{}
+func loadConfig() (*config, error) {
+	config := &config{}
 
 	bytes, err := ioutil.ReadFile("config.json")
 	if err != nil {
@@ -82,13 +222,81 @@ func initOauth() (*StudioConfig, error) {
 		config.ClientID = os.Getenv("CLIENT_ID")
 		config.ClientSecret = os.Getenv("CLIENT_SECRET")
 		config.URL = os.Getenv("URL")
+		config.APIURL = os.Getenv("API_URL")
+		config.SnapshotDir = os.Getenv("SNAPSHOT_DIR")
+		config.SnapshotTimeout, _ = strconv.Atoi(os.Getenv("SNAPSHOT_TIMEOUT"))
+		config.CookieKeys = splitList(os.Getenv("COOKIE_KEYS"))
+		config.PKCE, _ = strconv.ParseBool(os.Getenv("PKCE"))
+		config.RevokeURL = os.Getenv("REVOKE_URL")
+		config.Admins = splitList(os.Getenv("ADMINS"))
+		config.TokenKeys = splitList(os.Getenv("TOKEN_KEYS"))
+		config.Database = os.Getenv("DATABASE")
+		config.DatabaseURL = os.Getenv("DATABASE_URL")
+		config.FakeStudio, _ = strconv.ParseBool(os.Getenv("FAKE_STUDIO"))
+		config.AuthURL = os.Getenv("AUTH_URL")
+		config.DevAuth, _ = strconv.ParseBool(os.Getenv("DEV_AUTH"))
 	} else {
-		err = json.Unmarshal(bytes, &config)
+		err = json.Unmarshal(bytes, config)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	if config.FakeStudio {
+		config.APIURL = config.URL + fakeStudioPath + studiotest.APIPath
+	}
+
+	if config.APIURL == "" {
+		config.APIURL = studio.DefaultBaseURL
+	}
+
+	if config.SnapshotDir == "" {
+		config.SnapshotDir = "snapshots"
+	}
+
+	if config.SnapshotTimeout <= 0 {
+		config.SnapshotTimeout = 10
+	}
+
+	if config.DevAuth {
+		// The development auth server lets anyone sign in as anyone, so it must never guard real Studio data
+		if !config.FakeStudio && !loopbackURL(config.URL) {
+			return nil, errors.New("devAuth lets anyone sign in as any user. It needs fakeStudio, or a url of http on localhost.")
+		}
+		config.AuthURL = config.URL + devAuthPath
+		config.RevokeURL = ""
+	}
+
+	if config.AuthURL == "" {
+		config.AuthURL = defaultAuthURL
+	}
+	config.AuthURL = strings.TrimSuffix(config.AuthURL, "/")
+
+	if config.RevokeURL == "" {
+		config.RevokeURL = config.AuthURL + "/auth/token/revoke"
+	}
+
+	return config, nil
+}
+
+// loopbackURL reports whether rawURL is plain http on this machine only
+func loopbackURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "http" {
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func initOauth(config *config) *StudioConfig {
+	// Do not be alarmed by this call. This is simply because the Studio Auth server expects the clientId and secretId to be in query parameters rather than the Authorization header
+	oauth2.RegisterBrokenAuthHeaderProvider(config.AuthURL + "/auth/token")
+
 	conf := &StudioConfig{
 		Config: &oauth2.Config{
 			ClientID:     config.ClientID,
@@ -96,11 +304,13 @@ func initOauth() (*StudioConfig, error) {
 			Scopes:       []string{"full_user", "jobs"},
 			RedirectURL:  config.URL + "/callback",
 			Endpoint: oauth2.Endpoint{
-				AuthURL:  "https://authserver.bluebeam.com/auth/oauth/authorize",
-				TokenURL: "https://authserver.bluebeam.com/auth/token",
+				AuthURL:  config.AuthURL + "/auth/oauth/authorize",
+				TokenURL: config.AuthURL + "/auth/token",
 			},
 		},
+		PKCE:      config.PKCE,
+		RevokeURL: config.RevokeURL,
 	}
 
-	return conf, nil
+	return conf
 }
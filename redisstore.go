@@ -0,0 +1,555 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/oauth2"
+)
+
+// RedisStore keeps the records in Redis, or anything that speaks its protocol, so that several instances of the app can share them.
+// Writes that depend on what is stored use optimistic locking: the keys are WATCHed, read, and written in a MULTI that fails if another client changed them in between.
+type RedisStore struct {
+	// Client is connected to URL on Open unless it is set already, as redisstore_test.go does with miniredis
+	Client redis.UniversalClient
+	// URL is a redis:// or rediss:// URL
+	URL string
+	// Prefix is put in front of every key so the database can be shared, roundtripper: when empty
+	Prefix string
+	// Tokens encrypts the stored tokens. They are stored in plain text when it is nil.
+	Tokens *tokenCipher
+}
+
+// redisWatchRetries is how many times a write that lost an optimistic lock is tried again
+const redisWatchRetries = 5
+
+func (s *RedisStore) Open(ctx context.Context) error {
+	if s.Prefix == "" {
+		s.Prefix = "roundtripper:"
+	}
+
+	if s.Client == nil {
+		options, err := redis.ParseURL(s.URL)
+		if err != nil {
+			return fmt.Errorf("Parsing the Redis URL: %v", err)
+		}
+		s.Client = redis.NewClient(options)
+	}
+
+	if err := s.Client.Ping(ctx).Err(); err != nil {
+		s.Client.Close()
+		return fmt.Errorf("Connecting to Redis: %v", err)
+	}
+
+	return nil
+}
+
+func (s *RedisStore) Close() error {
+	return s.Client.Close()
+}
+
+// Keys of the records, and of the sets that index them
+func (s *RedisStore) tokenKey(userID string) string    { return s.Prefix + "token:" + userID }
+func (s *RedisStore) usersKey() string                 { return s.Prefix + "users" }
+func (s *RedisStore) loginSessionKey(id string) string { return s.Prefix + "loginsession:" + id }
+func (s *RedisStore) leaseKey(name string) string      { return s.Prefix + "lease:" + name }
+func (s *RedisStore) roundTripKey(id string) string    { return s.Prefix + "roundtrip:" + id }
+func (s *RedisStore) roundTripsKey() string            { return s.Prefix + "roundtrips" }
+func (s *RedisStore) prefsKey(userID string) string    { return s.Prefix + "prefs:" + userID }
+func (s *RedisStore) userSetKey(userID, kind string) string {
+	return s.Prefix + "user:" + userID + ":" + kind
+}
+
+// watch runs fn with keys WATCHed, trying again when another client changed them before the MULTI of fn ran
+func (s *RedisStore) watch(ctx context.Context, fn func(tx *redis.Tx) error, keys ...string) error {
+	for i := 0; i < redisWatchRetries; i++ {
+		err := s.Client.Watch(ctx, fn, keys...)
+		if err != redis.TxFailedErr {
+			return err
+		}
+	}
+	return fmt.Errorf("Too many concurrent changes to %v", keys)
+}
+
+// getJSON reads a record into v, returning an error wrapping ErrNotFound when there is none. kind and name describe the record in the error.
+func (s *RedisStore) getJSON(ctx context.Context, key, kind, name string, v interface{}) error {
+	data, err := s.Client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return fmt.Errorf("%s %w: %s", kind, ErrNotFound, name)
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// mgetJSON calls fn with each of the records whose ids are in an index set. key gives the key of the record for an id.
+func (s *RedisStore) mgetJSON(ctx context.Context, setKey string, key func(id string) string, fn func(data []byte) error) error {
+	ids, err := s.Client.SMembers(ctx, setKey).Result()
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = key(id)
+	}
+
+	values, err := s.Client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, v := range values {
+		// Records deleted or expired since the set was read come back as nil
+		data, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if err := fn([]byte(data)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scanJSON calls fn with each record whose key starts with the prefix of a kind of record, and the rest of its key
+func (s *RedisStore) scanJSON(ctx context.Context, prefix string, fn func(id string, data []byte) error) error {
+	iter := s.Client.Scan(ctx, 0, prefix+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		data, err := s.Client.Get(ctx, key).Bytes()
+		// Records deleted or expired since the scan found them are skipped
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(strings.TrimPrefix(key, prefix), data); err != nil {
+			return err
+		}
+	}
+
+	return iter.Err()
+}
+
+func (s *RedisStore) StoreToken(ctx context.Context, userID string, token *oauth2.Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	record, err := s.Tokens.Seal(userID, data)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, s.tokenKey(userID), record, 0)
+		pipe.SAdd(ctx, s.usersKey(), userID)
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) GetToken(ctx context.Context, userID string) (*oauth2.Token, error) {
+	record, err := s.Client.Get(ctx, s.tokenKey(userID)).Bytes()
+	if err == redis.Nil {
+		return nil, fmt.Errorf("Token %w: %s", ErrNotFound, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.Tokens.Open(userID, record)
+	if err != nil {
+		return nil, fmt.Errorf("Token of %s: %v", userID, err)
+	}
+
+	token := &oauth2.Token{}
+	if err := json.Unmarshal(data, token); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (s *RedisStore) DeleteToken(ctx context.Context, userID string) error {
+	_, err := s.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, s.tokenKey(userID))
+		pipe.SRem(ctx, s.usersKey(), userID)
+		return nil
+	})
+	return err
+}
+
+// ReencryptTokens rewrites the tokens one at a time. A token is only replaced if it was not refreshed since it was read, otherwise it is read again.
+func (s *RedisStore) ReencryptTokens(ctx context.Context) (int, error) {
+	users, err := s.ListUsers(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, userID := range users {
+		key := s.tokenKey(userID)
+		err := s.watch(ctx, func(tx *redis.Tx) error {
+			record, err := tx.Get(ctx, key).Bytes()
+			if err == redis.Nil || (err == nil && s.Tokens.Current(record)) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			data, err := s.Tokens.Open(userID, record)
+			if err != nil {
+				return fmt.Errorf("Token of %s: %v", userID, err)
+			}
+
+			sealed, err := s.Tokens.Seal(userID, data)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Set(ctx, key, sealed, 0)
+				return nil
+			})
+			if err == nil {
+				count++
+			}
+			return err
+		}, key)
+		if err != nil {
+			return count, err
+		}
+	}
+
+	return count, nil
+}
+
+func (s *RedisStore) ListUsers(ctx context.Context) ([]string, error) {
+	users, err := s.Client.SMembers(ctx, s.usersKey()).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(users)
+	return users, nil
+}
+
+func (s *RedisStore) DeleteUser(ctx context.Context, userID string) error {
+	sessionsKey := s.userSetKey(userID, "loginsessions")
+	roundTripsKey := s.userSetKey(userID, "roundtrips")
+
+	// The index sets are watched so a login or round-trip saved meanwhile is not left behind
+	return s.watch(ctx, func(tx *redis.Tx) error {
+		sessions, err := tx.SMembers(ctx, sessionsKey).Result()
+		if err != nil {
+			return err
+		}
+		roundTrips, err := tx.SMembers(ctx, roundTripsKey).Result()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, s.tokenKey(userID), s.prefsKey(userID), sessionsKey, roundTripsKey)
+			pipe.SRem(ctx, s.usersKey(), userID)
+			for _, id := range sessions {
+				pipe.Del(ctx, s.loginSessionKey(id))
+			}
+			for _, id := range roundTrips {
+				pipe.Del(ctx, s.roundTripKey(id))
+				pipe.SRem(ctx, s.roundTripsKey(), id)
+			}
+			return nil
+		})
+		return err
+	}, sessionsKey, roundTripsKey)
+}
+
+// StoreLoginSession lets Redis expire the session along with the cookie
+func (s *RedisStore) StoreLoginSession(ctx context.Context, ls *LoginSession) error {
+	data, err := json.Marshal(ls)
+	if err != nil {
+		return err
+	}
+
+	key := s.loginSessionKey(ls.ID)
+	_, err = s.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, data, 0)
+		if !ls.Expires.IsZero() {
+			pipe.ExpireAt(ctx, key, ls.Expires)
+		}
+		pipe.SAdd(ctx, s.userSetKey(ls.UserID, "loginsessions"), ls.ID)
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) GetLoginSession(ctx context.Context, id string) (*LoginSession, error) {
+	ls := &LoginSession{}
+	err := s.getJSON(ctx, s.loginSessionKey(id), "Login session", "", ls)
+	if errors.Is(err, ErrNotFound) {
+		// The id is a credential so it is left out of the error
+		return nil, fmt.Errorf("Login session %w", ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return ls, nil
+}
+
+func (s *RedisStore) ListUserLoginSessions(ctx context.Context, userID string) ([]*LoginSession, error) {
+	sessions := []*LoginSession{}
+	err := s.mgetJSON(ctx, s.userSetKey(userID, "loginsessions"), s.loginSessionKey, func(data []byte) error {
+		ls := &LoginSession{}
+		if err := json.Unmarshal(data, ls); err != nil {
+			return err
+		}
+		sessions = append(sessions, ls)
+		return nil
+	})
+
+	return sessions, err
+}
+
+// ListLoginSessions scans the keys of the login sessions, as they are only indexed per user
+func (s *RedisStore) ListLoginSessions(ctx context.Context) ([]*LoginSession, error) {
+	sessions := []*LoginSession{}
+	err := s.scanJSON(ctx, s.loginSessionKey(""), func(id string, data []byte) error {
+		ls := &LoginSession{}
+		if err := json.Unmarshal(data, ls); err != nil {
+			return err
+		}
+		sessions = append(sessions, ls)
+		return nil
+	})
+
+	return sessions, err
+}
+
+func (s *RedisStore) DeleteLoginSession(ctx context.Context, id string) error {
+	ls, err := s.GetLoginSession(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, s.loginSessionKey(id))
+		pipe.SRem(ctx, s.userSetKey(ls.UserID, "loginsessions"), id)
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) DeleteUserLoginSessions(ctx context.Context, userID string) error {
+	sessionsKey := s.userSetKey(userID, "loginsessions")
+
+	return s.watch(ctx, func(tx *redis.Tx) error {
+		sessions, err := tx.SMembers(ctx, sessionsKey).Result()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, sessionsKey)
+			for _, id := range sessions {
+				pipe.Del(ctx, s.loginSessionKey(id))
+			}
+			return nil
+		})
+		return err
+	}, sessionsKey)
+}
+
+// AcquireLease is what keeps instances from rotating the same refresh token at once. The expiry is kept in the record rather than left to Redis alone so that a lease is judged by the same clock it was taken with.
+func (s *RedisStore) AcquireLease(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	key := s.leaseKey(name)
+	acquired := false
+
+	err := s.Client.Watch(ctx, func(tx *redis.Tx) error {
+		data, err := tx.Get(ctx, key).Bytes()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		if err == nil {
+			held := &lease{}
+			if err := json.Unmarshal(data, held); err != nil {
+				return err
+			}
+			if held.Owner != owner && time.Now().Before(held.Expires) {
+				return nil
+			}
+		}
+
+		data, err = json.Marshal(&lease{Owner: owner, Expires: time.Now().Add(ttl)})
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			// Redis only cleans up the record, well after it has expired
+			pipe.Set(ctx, key, data, ttl+time.Minute)
+			return nil
+		})
+		acquired = err == nil
+		return err
+	}, key)
+
+	// Someone else changed the lease between the read and the write, so they hold it now
+	if err == redis.TxFailedErr {
+		return false, nil
+	}
+
+	return acquired && err == nil, err
+}
+
+func (s *RedisStore) ReleaseLease(ctx context.Context, name, owner string) error {
+	key := s.leaseKey(name)
+
+	return s.watch(ctx, func(tx *redis.Tx) error {
+		data, err := tx.Get(ctx, key).Bytes()
+		if err == redis.Nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		held := &lease{}
+		if err := json.Unmarshal(data, held); err != nil {
+			return err
+		}
+		if held.Owner != owner {
+			return nil
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, key)
+			return nil
+		})
+		return err
+	}, key)
+}
+
+func (s *RedisStore) SaveRoundTrip(ctx context.Context, rt *RoundTrip) error {
+	data, err := json.Marshal(rt)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, s.roundTripKey(rt.ID), data, 0)
+		pipe.SAdd(ctx, s.roundTripsKey(), rt.ID)
+		pipe.SAdd(ctx, s.userSetKey(rt.UserID, "roundtrips"), rt.ID)
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) GetRoundTrip(ctx context.Context, id string) (*RoundTrip, error) {
+	rt := &RoundTrip{}
+	if err := s.getJSON(ctx, s.roundTripKey(id), "Round-trip", id, rt); err != nil {
+		return nil, err
+	}
+
+	return rt, nil
+}
+
+func (s *RedisStore) ListRoundTrips(ctx context.Context) ([]*RoundTrip, error) {
+	return s.listRoundTrips(ctx, s.roundTripsKey())
+}
+
+func (s *RedisStore) ListUserRoundTrips(ctx context.Context, userID string) ([]*RoundTrip, error) {
+	return s.listRoundTrips(ctx, s.userSetKey(userID, "roundtrips"))
+}
+
+// listRoundTrips reads the round-trips of an index set, oldest first
+func (s *RedisStore) listRoundTrips(ctx context.Context, setKey string) ([]*RoundTrip, error) {
+	roundTrips := []*RoundTrip{}
+	err := s.mgetJSON(ctx, setKey, s.roundTripKey, func(data []byte) error {
+		rt := &RoundTrip{}
+		if err := json.Unmarshal(data, rt); err != nil {
+			return err
+		}
+		roundTrips = append(roundTrips, rt)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(roundTrips, func(i, j int) bool { return roundTrips[i].Created.Before(roundTrips[j].Created) })
+	return roundTrips, nil
+}
+
+func (s *RedisStore) DeleteRoundTrip(ctx context.Context, id string) error {
+	rt, err := s.GetRoundTrip(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, s.roundTripKey(id))
+		pipe.SRem(ctx, s.roundTripsKey(), id)
+		pipe.SRem(ctx, s.userSetKey(rt.UserID, "roundtrips"), id)
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) GetUserPrefs(ctx context.Context, userID string) (*UserPrefs, error) {
+	prefs := &UserPrefs{}
+	err := s.getJSON(ctx, s.prefsKey(userID), "Preferences", userID, prefs)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	return prefs, nil
+}
+
+func (s *RedisStore) SaveUserPrefs(ctx context.Context, userID string, prefs *UserPrefs) error {
+	data, err := json.Marshal(prefs)
+	if err != nil {
+		return err
+	}
+
+	return s.Client.Set(ctx, s.prefsKey(userID), data, 0).Err()
+}
+
+// ListUserPrefs scans the keys of the preferences, which are not indexed
+func (s *RedisStore) ListUserPrefs(ctx context.Context) (map[string]*UserPrefs, error) {
+	prefs := map[string]*UserPrefs{}
+	err := s.scanJSON(ctx, s.prefsKey(""), func(userID string, data []byte) error {
+		p := &UserPrefs{}
+		if err := json.Unmarshal(data, p); err != nil {
+			return err
+		}
+		prefs[userID] = p
+		return nil
+	})
+
+	return prefs, err
+}
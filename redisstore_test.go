@@ -0,0 +1,107 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package main
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/oauth2"
+)
+
+func TestRedisStore(t *testing.T) {
+	server := miniredis.RunT(t)
+	testDataStore(t, &RedisStore{Client: redis.NewClient(&redis.Options{Addr: server.Addr()}), Tokens: testTokenCipher(t)})
+}
+
+// TestRedisStoreWatchConflict has another instance refresh a token between the read and the write of ReencryptTokens. The write must fail its optimistic lock and be tried again with the refreshed token rather than overwrite it.
+func TestRedisStoreWatchConflict(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+
+	other := &RedisStore{Client: redis.NewClient(&redis.Options{Addr: server.Addr()})}
+	if err := other.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+
+	if err := other.StoreToken(ctx, "user", &oauth2.Token{AccessToken: "access", RefreshToken: "refresh"}); err != nil {
+		t.Fatal(err)
+	}
+
+	refreshed := &oauth2.Token{AccessToken: "access 2", RefreshToken: "refresh 2", Expiry: time.Now().Add(time.Hour).Round(time.Second)}
+	hook := &refreshOnGet{key: other.tokenKey("user"), refresh: func() error {
+		return other.StoreToken(ctx, "user", refreshed)
+	}}
+
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	client.AddHook(hook)
+	store := &RedisStore{Client: client, Tokens: testTokenCipher(t)}
+	if err := store.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	count, err := store.ReencryptTokens(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("ReencryptTokens rewrote %d tokens, want 1", count)
+	}
+	if hook.gets < 2 {
+		t.Errorf("The token was read %d times, want it read again after the conflict", hook.gets)
+	}
+
+	token, err := store.GetToken(ctx, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sameJSON(token, refreshed); err != nil {
+		t.Errorf("The refreshed token was overwritten: %v", err)
+	}
+}
+
+// refreshOnGet calls refresh after the first GET of key, as if another instance wrote it right after it was read
+type refreshOnGet struct {
+	key     string
+	refresh func() error
+
+	mu   sync.Mutex
+	gets int
+}
+
+func (h *refreshOnGet) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h *refreshOnGet) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		err := next(ctx, cmd)
+		if args := cmd.Args(); cmd.Name() == "get" && len(args) == 2 && args[1] == h.key {
+			h.mu.Lock()
+			h.gets++
+			first := h.gets == 1
+			h.mu.Unlock()
+			if first {
+				if err := h.refresh(); err != nil {
+					return err
+				}
+			}
+		}
+		return err
+	}
+}
+
+func (h *refreshOnGet) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
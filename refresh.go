@@ -0,0 +1,118 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// refreshLeaseTTL bounds how long an instance holds the right to refresh a user's token. It outlasts a token request so a lease only expires early when its holder died.
+const refreshLeaseTTL = 30 * time.Second
+
+// refreshPollInterval is how often an instance waiting on another's refresh looks for the new token
+const refreshPollInterval = 250 * time.Millisecond
+
+// refresh trades a user's refresh token for a new token. Studio refresh tokens are single use, so two refreshes of the same token would lock the user out.
+// Within the process, concurrent refreshes of a user share one request through singleflight. Across instances, the refresh is guarded by a lease in the DataStore and the other instances pick up the winner's token from the DataStore.
+func (c *StudioConfig) refresh(ctx context.Context, userID string, stale *oauth2.Token) (*oauth2.Token, error) {
+	result, err, _ := c.refreshes.Do(userID, func() (interface{}, error) {
+		// The refresh is shared, so it must not be cut short by whichever request happened to start it
+		refreshCtx, cancel := context.WithTimeout(context.Background(), refreshLeaseTTL)
+		defer cancel()
+		if hc := ctx.Value(oauth2.HTTPClient); hc != nil {
+			refreshCtx = context.WithValue(refreshCtx, oauth2.HTTPClient, hc)
+		}
+
+		return c.refreshOnce(refreshCtx, userID, stale)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*oauth2.Token), nil
+}
+
+// refreshOnce takes the user's refresh lease and refreshes the token, unless another instance turns out to have refreshed it already
+func (c *StudioConfig) refreshOnce(ctx context.Context, userID string, stale *oauth2.Token) (*oauth2.Token, error) {
+	owner, err := newLeaseOwner()
+	if err != nil {
+		return nil, err
+	}
+	lease := "refresh:" + userID
+
+	for {
+		if token, ok := rotatedToken(ctx, userID, stale); ok {
+			return token, nil
+		}
+
+		acquired, err := env.DataStore.AcquireLease(ctx, lease, owner, refreshLeaseTTL)
+		if err != nil {
+			return nil, err
+		}
+		if acquired {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.New("Timed out waiting for the token to be refreshed by another instance")
+		case <-time.After(refreshPollInterval):
+		}
+	}
+	// The lease is released even when the refresh timed out
+	defer env.DataStore.ReleaseLease(context.Background(), lease, owner)
+
+	// Look again now that nobody else can refresh, as the holder of the previous lease may have just finished
+	if token, ok := rotatedToken(ctx, userID, stale); ok {
+		return token, nil
+	}
+
+	// The stored refresh token is the latest one, even when its access token has expired as well
+	refreshToken := stale.RefreshToken
+	if stored, err := env.DataStore.GetToken(ctx, userID); err == nil && stored.RefreshToken != "" {
+		refreshToken = stored.RefreshToken
+	}
+
+	token, err := c.Config.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		return nil, err
+	}
+
+	// A token deleted during the refresh belonged to a user who logged out or was revoked, and storing the new one would sign them back in
+	if _, err := env.DataStore.GetToken(ctx, userID); errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("The token of %s was deleted while it was being refreshed: %w", userID, err)
+	}
+
+	// The new token must be stored before the lease is released, or a waiting instance would spend the old refresh token
+	if err := c.StoreToken(ctx, userID, token); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// rotatedToken returns the stored token of a user when it is valid and newer than stale
+func rotatedToken(ctx context.Context, userID string, stale *oauth2.Token) (*oauth2.Token, bool) {
+	stored, err := env.DataStore.GetToken(ctx, userID)
+	if err != nil || !stored.Valid() || stored.AccessToken == stale.AccessToken {
+		return nil, false
+	}
+	return stored, true
+}
+
+func newLeaseOwner() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
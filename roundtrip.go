@@ -0,0 +1,322 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"bluebeam/gosessionroundtripper/studio"
+)
+
+// States of a round-trip as a whole
+const (
+	roundTripCreating  = "Creating"
+	roundTripActive    = "Active"
+	roundTripFinishing = "Finishing"
+	roundTripComplete  = "Complete"
+	roundTripFailed    = "Failed"
+)
+
+// roundTripStep is the last checkpoint reached by the Session level steps of a round-trip. Steps only move forward so a restarted workflow skips everything up to and including Step.
+type roundTripStep int
+
+const (
+	stepNew roundTripStep = iota
+	stepFilesReady
+	stepSessionCreated
+	stepCheckedOut
+	stepFinalizing
+	stepSnapshotsReady
+	stepSessionDeleted
+	stepFinished
+)
+
+var roundTripStepNames = []string{"New", "Files ready", "Session created", "Checked out", "Finalizing", "Snapshots ready", "Session deleted", "Finished"}
+
+func (s roundTripStep) String() string {
+	if int(s) < len(roundTripStepNames) {
+		return roundTripStepNames[s]
+	}
+	return fmt.Sprintf("Step %d", int(s))
+}
+
+// fileStep is the last checkpoint reached by a single file of a round-trip
+type fileStep int
+
+const (
+	fileNew fileStep = iota
+	fileUploaded
+	fileCheckedOut
+	fileSnapshotStarted
+	fileSnapshotDownloaded
+	fileCheckinStarted
+	fileRevisionUploaded
+	fileCheckedIn
+	fileFlattenStarted
+	fileShared
+)
+
+var fileStepNames = []string{"New", "Uploaded", "Checked out", "Snapshot started", "Snapshot downloaded", "Checkin started", "Revision uploaded", "Checked in", "Flatten started", "Complete"}
+
+func (s fileStep) String() string {
+	if int(s) < len(fileStepNames) {
+		return fileStepNames[s]
+	}
+	return fmt.Sprintf("Step %d", int(s))
+}
+
+// RoundTrip is the persisted record of a Session created by the app, from upload through to the share links. It is saved to the DataStore after every step.
+type RoundTrip struct {
+	ID          string `json:"id"`
+	UserID      string `json:"userId"`
+	SessionName string `json:"sessionName"`
+	SessionID   string `json:"sessionId"`
+	ProjectID   string `json:"projectId"`
+	// SessionEndDate is when Studio closes the Session
+	SessionEndDate time.Time        `json:"sessionEndDate"`
+	State          string           `json:"state"`
+	Step           roundTripStep    `json:"step"`
+	Error          string           `json:"error,omitempty"`
+	Files          []*RoundTripFile `json:"files"`
+	// Compensations undo the steps completed so far. They are cleared once a workflow gets past the point where undoing makes sense.
+	Compensations []*Compensation `json:"compensations,omitempty"`
+	Created       time.Time       `json:"created"`
+	Updated       time.Time       `json:"updated"`
+}
+
+// RoundTripFile is a single file of a RoundTrip
+type RoundTripFile struct {
+	Name          string   `json:"name"`
+	FileProjectID int      `json:"fileProjectId"`
+	FileSessionID int      `json:"fileSessionId"`
+	Step          fileStep `json:"step"`
+	Error         string   `json:"error,omitempty"`
+	SnapshotPath  string   `json:"snapshotPath,omitempty"`
+	// SnapshotSize and SnapshotCRC describe the snapshot as it was downloaded, so it can be checked before it is checked in
+	SnapshotSize      int64  `json:"snapshotSize,omitempty"`
+	SnapshotCRC       string `json:"snapshotCrc,omitempty"`
+	UploadURL         string `json:"uploadUrl,omitempty"`
+	UploadContentType string `json:"uploadContentType,omitempty"`
+	FlattenJobID      int    `json:"flattenJobId,omitempty"`
+	ShareLink         string `json:"shareLink,omitempty"`
+}
+
+func newRoundTrip(userID, sessionName, projectID string) (*RoundTrip, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	return &RoundTrip{
+		ID:          hex.EncodeToString(id),
+		UserID:      userID,
+		SessionName: sessionName,
+		ProjectID:   projectID,
+		State:       roundTripCreating,
+		Created:     now,
+		Updated:     now,
+	}, nil
+}
+
+// saveRoundTrip checkpoints a round-trip in the DataStore. The checkpoint is saved even when the request that made the progress has gone.
+func saveRoundTrip(rt *RoundTrip) error {
+	rt.Updated = time.Now().UTC()
+	if err := env.DataStore.SaveRoundTrip(context.Background(), rt); err != nil {
+		fmt.Printf("Saving round-trip %s: %v\n", rt.ID, err)
+		return err
+	}
+	return nil
+}
+
+// Failed reports whether the file hit an error
+func (f *RoundTripFile) Failed() bool {
+	return f.Error != ""
+}
+
+// failedFiles counts the files of a round-trip that hit an error
+func (rt *RoundTrip) failedFiles() int {
+	n := 0
+	for _, f := range rt.Files {
+		if f.Failed() {
+			n++
+		}
+	}
+	return n
+}
+
+// checkinRetryable reports whether a round-trip failed checking in files after its Session was deleted, so finishing it again retries those files
+func (rt *RoundTrip) checkinRetryable() bool {
+	return rt.State == roundTripFailed && rt.Step == stepSessionDeleted
+}
+
+// snapshotPath is where the snapshot of a file is kept between the download and the checkin. It is outside the temp directory so a restart can pick it up.
+func (rt *RoundTrip) snapshotPath(f *RoundTripFile) string {
+	return filepath.Join(env.SnapshotDir, rt.ID, fmt.Sprintf("%d.pdf", f.FileProjectID))
+}
+
+// removeSnapshots deletes any snapshots still kept for a round-trip
+func (rt *RoundTrip) removeSnapshots() {
+	os.RemoveAll(filepath.Join(env.SnapshotDir, rt.ID))
+}
+
+// roundTripLeaseTTL bounds how long a round-trip stays claimed by an instance that stopped renewing its lease
+const roundTripLeaseTTL = time.Minute
+
+// errRoundTripBusy is returned when another instance is working on a round-trip
+var errRoundTripBusy = errors.New("This round-trip is being worked on by another instance, try again later")
+
+// roundTripLease is an instance's claim on a round-trip while it creates, finishes or rolls it back, so that other instances resuming at startup leave it alone
+type roundTripLease struct {
+	name, owner string
+	stop        chan struct{}
+	once        sync.Once
+}
+
+// leaseRoundTrip takes the lease of a round-trip and renews it until it is released. It fails with errRoundTripBusy when another instance holds it.
+func leaseRoundTrip(id string) (*roundTripLease, error) {
+	owner, err := newLeaseOwner()
+	if err != nil {
+		return nil, err
+	}
+
+	l := &roundTripLease{name: "roundtrip:" + id, owner: owner, stop: make(chan struct{})}
+	acquired, err := env.DataStore.AcquireLease(context.Background(), l.name, l.owner, roundTripLeaseTTL)
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, errRoundTripBusy
+	}
+
+	go l.renew()
+	return l, nil
+}
+
+// renew keeps the lease from expiring while the work on the round-trip runs
+func (l *roundTripLease) renew() {
+	ticker := time.NewTicker(roundTripLeaseTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+
+		acquired, err := env.DataStore.AcquireLease(context.Background(), l.name, l.owner, roundTripLeaseTTL)
+		if err != nil {
+			fmt.Printf("Renewing lease %s: %v\n", l.name, err)
+		} else if !acquired {
+			fmt.Printf("Renewing lease %s: taken by another instance\n", l.name)
+			return
+		}
+	}
+}
+
+// Release stops renewing the lease and gives it up. It does nothing on a nil lease or one already released.
+func (l *roundTripLease) Release() {
+	if l == nil {
+		return
+	}
+	l.once.Do(func() {
+		close(l.stop)
+		env.DataStore.ReleaseLease(context.Background(), l.name, l.owner)
+	})
+}
+
+// resumeRoundTrips picks up the round-trips that were being created or finished when the process last stopped. Round-trips leased by another instance are still being worked on and are left alone.
+func resumeRoundTrips() {
+	roundTrips, err := env.DataStore.ListRoundTrips(context.Background())
+	if err != nil {
+		fmt.Println("Resuming round-trips:", err)
+		return
+	}
+
+	for _, rt := range roundTrips {
+		if !rt.resumable() {
+			continue
+		}
+
+		lease, err := leaseRoundTrip(rt.ID)
+		if err != nil {
+			fmt.Printf("Resuming round-trip %s: %v\n", rt.ID, err)
+			continue
+		}
+
+		if err := resumeRoundTrip(rt.ID, lease); err != nil {
+			fmt.Printf("Resuming round-trip %s: %v\n", rt.ID, err)
+			lease.Release()
+		}
+	}
+}
+
+// resumable reports whether a round-trip was left part way through by a process that stopped
+func (rt *RoundTrip) resumable() bool {
+	return rt.State == roundTripCreating || rt.State == roundTripFinishing || (rt.State == roundTripFailed && rt.pendingCompensations())
+}
+
+// resumeRoundTrip carries on with a round-trip under lease, which is handed to the work that resumes it
+func resumeRoundTrip(id string, lease *roundTripLease) error {
+	// The listed record may have moved on before the lease was taken
+	rt, err := env.DataStore.GetRoundTrip(context.Background(), id)
+	if err != nil {
+		return err
+	}
+	if !rt.resumable() {
+		lease.Release()
+		return nil
+	}
+
+	client, err := userStudioClient(rt.UserID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Resuming round-trip %s: %s at %s\n", rt.ID, rt.State, rt.Step)
+
+	switch rt.State {
+	case roundTripCreating:
+		go func() {
+			defer lease.Release()
+			// Files that had not finished uploading went with the request, so only a create that got past the uploads can carry on
+			if rt.Step < stepFilesReady {
+				abortCreate(client, rt, errors.New("The upload was interrupted by a restart"))
+				return
+			}
+			runCreate(context.Background(), client, rt)
+		}()
+	case roundTripFailed:
+		// A rollback that did not complete is tried again
+		go func() {
+			defer lease.Release()
+			rollback(context.Background(), client, rt, checkpoint(rt))
+		}()
+	case roundTripFinishing:
+		job := newFinishJob(rt, client)
+		job.lease = lease
+		return env.Jobs.Enqueue(job)
+	}
+	return nil
+}
+
+// userStudioClient creates a Studio client from a user's stored token for work done outside of a request
+func userStudioClient(userID string) (*studio.Client, error) {
+	token, err := env.DataStore.GetToken(context.Background(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newStudioClient(env.OAuthConfig.TokenSource(context.Background(), userID, token))
+}
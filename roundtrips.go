@@ -0,0 +1,149 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"bluebeam/gosessionroundtripper/studio"
+)
+
+// sessionDetailsLimit is how many active Sessions the dashboard looks up in Studio at once
+const sessionDetailsLimit = 4
+
+// sessionDetailsTimeout bounds the lookup of one Session, so a slow Studio shows the stored details rather than hold up the page
+const sessionDetailsTimeout = 5 * time.Second
+
+// roundTripView is a round-trip as shown on the dashboard, with the live Session details for active round-trips
+type roundTripView struct {
+	*RoundTrip
+	JoinURL    string
+	Attendees  int
+	EndDate    string
+	FailedStep string
+	// RetryCheckin is set when finishing the round-trip again retries the files that could not be checked in
+	RetryCheckin bool
+	Live         bool
+}
+
+// roundTripsPage is the dashboard of the user's round-trips so a session can be found and finished after the create page is gone
+func roundTripsPage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	u := ctx.Value("user").(user)
+
+	client, err := getStudioClient(ctx)
+	if err != nil {
+		redirectToError(w, r, err)
+		return
+	}
+
+	roundTrips, err := env.DataStore.ListUserRoundTrips(ctx, u.UserID)
+	if err != nil {
+		redirectToError(w, r, err)
+		return
+	}
+
+	dashboard := struct {
+		UserID    string
+		Active    []*roundTripView
+		Finishing []*roundTripView
+		Failed    []*roundTripView
+		Complete  []*roundTripView
+		CSRFToken string
+		Degraded  string
+	}{UserID: u.UserID, CSRFToken: csrfToken(r)}
+
+	sort.Slice(roundTrips, func(i, j int) bool {
+		return roundTrips[i].Created.After(roundTrips[j].Created)
+	})
+
+	var details errgroup.Group
+	details.SetLimit(sessionDetailsLimit)
+
+	for _, rt := range roundTrips {
+		view := &roundTripView{RoundTrip: rt, Attendees: -1}
+		if !rt.SessionEndDate.IsZero() {
+			view.EndDate = rt.SessionEndDate.Local().Format("Jan 2, 2006 15:04")
+		}
+
+		switch rt.State {
+		case roundTripActive:
+			details.Go(func() error {
+				addSessionDetails(ctx, client, view)
+				return nil
+			})
+			dashboard.Active = append(dashboard.Active, view)
+		case roundTripCreating, roundTripFinishing:
+			dashboard.Finishing = append(dashboard.Finishing, view)
+		case roundTripFailed:
+			view.FailedStep = failedStep(rt)
+			view.RetryCheckin = rt.checkinRetryable()
+			dashboard.Failed = append(dashboard.Failed, view)
+		case roundTripComplete:
+			dashboard.Complete = append(dashboard.Complete, view)
+		}
+	}
+
+	details.Wait()
+
+	// The details of active Sessions are missing while Studio is down
+	dashboard.Degraded = studioDegraded()
+
+	html, err := Asset("assets/roundtrips.html")
+	if err != nil {
+		redirectToError(w, r, err)
+		return
+	}
+
+	t, _ := template.New("roundTrips").Parse(string(html))
+
+	t.Execute(w, dashboard)
+}
+
+// addSessionDetails fills in the join link, attendee count and end date of an active round-trip from Studio. The stored details are kept when Studio cannot be reached in time.
+func addSessionDetails(ctx context.Context, client *studio.Client, view *roundTripView) {
+	view.JoinURL = "https://studio.bluebeam.com/join.html?ID=" + view.SessionID
+
+	ctx, cancel := context.WithTimeout(ctx, sessionDetailsTimeout)
+	defer cancel()
+
+	session, err := client.Sessions.Get(ctx, view.SessionID)
+	if err != nil {
+		fmt.Printf("Session %s: %v\n", view.SessionID, err)
+		return
+	}
+	view.Live = true
+
+	if session.InviteURL != "" {
+		view.JoinURL = session.InviteURL
+	}
+	if end, err := time.Parse(time.RFC3339, session.SessionEndDate); err == nil {
+		view.EndDate = end.Local().Format("Jan 2, 2006 15:04")
+	}
+
+	users, err := client.Sessions.ListUsers(ctx, view.SessionID, &studio.ListOptions{Take: 1})
+	if err != nil {
+		fmt.Printf("Session %s users: %v\n", view.SessionID, err)
+		return
+	}
+	view.Attendees = users.TotalCount
+}
+
+// failedStep describes where a failed round-trip stopped. Files record the last step they completed, so the failure happened in the step after it.
+func failedStep(rt *RoundTrip) string {
+	for _, f := range rt.Files {
+		if f.Failed() {
+			return fmt.Sprintf("%s, after %s", f.Name, f.Step)
+		}
+	}
+	return fmt.Sprintf("After %s", rt.Step)
+}
@@ -0,0 +1,529 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"golang.org/x/oauth2"
+)
+
+// SQLStore keeps the records in a SQL database through database/sql. Postgres is the production database; SQLite is supported for local use.
+// Records are stored as JSON with the columns needed to look them up alongside, so new fields do not need a migration.
+type SQLStore struct {
+	DB *sql.DB
+	// Driver is postgres or sqlite3
+	Driver string
+	// Source is the data source name passed to the driver
+	Source string
+	// Tokens encrypts the stored tokens. They are stored in plain text when it is nil.
+	Tokens *tokenCipher
+}
+
+// sqlMigrations are the versions of the schema, applied in order at startup. Applied migrations must never be changed; add a new one instead.
+var sqlMigrations = []string{
+	// 1: initial schema
+	`CREATE TABLE tokens (
+		user_id TEXT PRIMARY KEY,
+		record TEXT NOT NULL
+	);
+	CREATE TABLE login_sessions (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		expires BIGINT NOT NULL,
+		data TEXT NOT NULL
+	);
+	CREATE INDEX login_sessions_user_id ON login_sessions (user_id);
+	CREATE TABLE leases (
+		name TEXT PRIMARY KEY,
+		owner TEXT NOT NULL,
+		expires BIGINT NOT NULL
+	);
+	CREATE TABLE round_trips (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		state TEXT NOT NULL,
+		created BIGINT NOT NULL,
+		data TEXT NOT NULL
+	);
+	CREATE INDEX round_trips_user_id ON round_trips (user_id);
+	CREATE TABLE user_prefs (
+		user_id TEXT PRIMARY KEY,
+		data TEXT NOT NULL
+	);`,
+}
+
+func (s *SQLStore) Open(ctx context.Context) error {
+	if s.Driver != "postgres" && s.Driver != "sqlite3" {
+		return fmt.Errorf("Unsupported SQL driver: %s", s.Driver)
+	}
+
+	db, err := sql.Open(s.Driver, s.Source)
+	if err != nil {
+		return err
+	}
+
+	// SQLite allows a single writer, so sharing one connection avoids "database is locked" errors
+	if s.Driver == "sqlite3" {
+		db.SetMaxOpenConns(1)
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return fmt.Errorf("Connecting to the %s database: %v", s.Driver, err)
+	}
+
+	s.DB = db
+	if err := s.migrate(ctx); err != nil {
+		db.Close()
+		return fmt.Errorf("Migrating the %s database: %v", s.Driver, err)
+	}
+
+	return nil
+}
+
+func (s *SQLStore) Close() error {
+	return s.DB.Close()
+}
+
+// migrate applies the migrations the database has not seen yet. Each migration runs in its own transaction; on Postgres an advisory lock, taken before anything else, keeps instances starting at the same time from applying it twice.
+func (s *SQLStore) migrate(ctx context.Context) error {
+	// The lock belongs to a connection, so everything is done on the one that holds it
+	conn, err := s.DB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if s.Driver == "postgres" {
+		if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock(7363746)`); err != nil {
+			return err
+		}
+		defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(7363746)`)
+	}
+
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY, applied BIGINT NOT NULL)`)
+	if err != nil {
+		return err
+	}
+
+	for i, migration := range sqlMigrations {
+		version := i + 1
+
+		var applied int
+		err := conn.QueryRowContext(ctx, s.rebind(`SELECT COUNT(*) FROM schema_migrations WHERE version = ?`), version).Scan(&applied)
+		if err != nil {
+			return err
+		}
+		if applied > 0 {
+			continue
+		}
+
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if err := s.applyMigration(ctx, tx, version, migration); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyMigration runs the statements of a migration and records it as applied
+func (s *SQLStore) applyMigration(ctx context.Context, tx *sql.Tx, version int, migration string) error {
+	fmt.Printf("Applying database migration %d\n", version)
+	for _, statement := range strings.Split(migration, ";") {
+		if strings.TrimSpace(statement) == "" {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("Migration %d: %v", version, err)
+		}
+	}
+
+	_, err := tx.ExecContext(ctx, s.rebind(`INSERT INTO schema_migrations (version, applied) VALUES (?, ?)`), version, time.Now().Unix())
+	return err
+}
+
+// rebind turns the ? placeholders of a query into the $1 style Postgres expects
+func (s *SQLStore) rebind(query string) string {
+	if s.Driver != "postgres" {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (s *SQLStore) exec(ctx context.Context, query string, args ...interface{}) error {
+	_, err := s.DB.ExecContext(ctx, s.rebind(query), args...)
+	return err
+}
+
+func (s *SQLStore) transaction(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// getJSON reads the data column of a single row into v, returning an error wrapping ErrNotFound when there is none. kind names the record in the error.
+func (s *SQLStore) getJSON(ctx context.Context, v interface{}, kind, key, query string, args ...interface{}) error {
+	var data string
+	err := s.DB.QueryRowContext(ctx, s.rebind(query), args...).Scan(&data)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%s %w: %s", kind, ErrNotFound, key)
+	}
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(data), v)
+}
+
+func (s *SQLStore) StoreToken(ctx context.Context, userID string, token *oauth2.Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	record, err := s.Tokens.Seal(userID, data)
+	if err != nil {
+		return err
+	}
+
+	return s.exec(ctx, `INSERT INTO tokens (user_id, record) VALUES (?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET record = excluded.record`, userID, string(record))
+}
+
+func (s *SQLStore) GetToken(ctx context.Context, userID string) (*oauth2.Token, error) {
+	var record string
+	err := s.DB.QueryRowContext(ctx, s.rebind(`SELECT record FROM tokens WHERE user_id = ?`), userID).Scan(&record)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("Token %w: %s", ErrNotFound, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.Tokens.Open(userID, []byte(record))
+	if err != nil {
+		return nil, fmt.Errorf("Token of %s: %v", userID, err)
+	}
+
+	token := &oauth2.Token{}
+	if err := json.Unmarshal(data, token); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (s *SQLStore) DeleteToken(ctx context.Context, userID string) error {
+	return s.exec(ctx, `DELETE FROM tokens WHERE user_id = ?`, userID)
+}
+
+func (s *SQLStore) ReencryptTokens(ctx context.Context) (int, error) {
+	count := 0
+	err := s.transaction(ctx, func(tx *sql.Tx) error {
+		query := `SELECT user_id, record FROM tokens`
+		if s.Driver == "postgres" {
+			// Keep refreshes from storing a token in between the read and the write
+			query += ` FOR UPDATE`
+		}
+
+		rows, err := tx.QueryContext(ctx, query)
+		if err != nil {
+			return err
+		}
+
+		records := map[string]string{}
+		for rows.Next() {
+			var userID, record string
+			if err := rows.Scan(&userID, &record); err != nil {
+				rows.Close()
+				return err
+			}
+			if !s.Tokens.Current([]byte(record)) {
+				records[userID] = record
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for userID, record := range records {
+			data, err := s.Tokens.Open(userID, []byte(record))
+			if err != nil {
+				return fmt.Errorf("Token of %s: %v", userID, err)
+			}
+
+			sealed, err := s.Tokens.Seal(userID, data)
+			if err != nil {
+				return err
+			}
+
+			if _, err := tx.ExecContext(ctx, s.rebind(`UPDATE tokens SET record = ? WHERE user_id = ?`), string(sealed), userID); err != nil {
+				return err
+			}
+		}
+
+		count = len(records)
+		return nil
+	})
+
+	return count, err
+}
+
+func (s *SQLStore) ListUsers(ctx context.Context) ([]string, error) {
+	rows, err := s.DB.QueryContext(ctx, `SELECT user_id FROM tokens ORDER BY user_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []string{}
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		users = append(users, userID)
+	}
+
+	return users, rows.Err()
+}
+
+func (s *SQLStore) DeleteUser(ctx context.Context, userID string) error {
+	return s.transaction(ctx, func(tx *sql.Tx) error {
+		for _, table := range []string{"tokens", "login_sessions", "user_prefs", "round_trips"} {
+			if _, err := tx.ExecContext(ctx, s.rebind(`DELETE FROM `+table+` WHERE user_id = ?`), userID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *SQLStore) StoreLoginSession(ctx context.Context, ls *LoginSession) error {
+	data, err := json.Marshal(ls)
+	if err != nil {
+		return err
+	}
+
+	return s.exec(ctx, `INSERT INTO login_sessions (id, user_id, expires, data) VALUES (?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET user_id = excluded.user_id, expires = excluded.expires, data = excluded.data`,
+		ls.ID, ls.UserID, ls.Expires.Unix(), string(data))
+}
+
+func (s *SQLStore) GetLoginSession(ctx context.Context, id string) (*LoginSession, error) {
+	ls := &LoginSession{}
+	err := s.getJSON(ctx, ls, "Login session", "", `SELECT data FROM login_sessions WHERE id = ?`, id)
+	if errors.Is(err, ErrNotFound) {
+		// The id is a credential so it is left out of the error
+		return nil, fmt.Errorf("Login session %w", ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return ls, nil
+}
+
+func (s *SQLStore) ListUserLoginSessions(ctx context.Context, userID string) ([]*LoginSession, error) {
+	return s.listLoginSessions(ctx, `SELECT data FROM login_sessions WHERE user_id = ?`, userID)
+}
+
+func (s *SQLStore) ListLoginSessions(ctx context.Context) ([]*LoginSession, error) {
+	return s.listLoginSessions(ctx, `SELECT data FROM login_sessions`)
+}
+
+func (s *SQLStore) listLoginSessions(ctx context.Context, query string, args ...interface{}) ([]*LoginSession, error) {
+	rows, err := s.DB.QueryContext(ctx, s.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*LoginSession{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+
+		ls := &LoginSession{}
+		if err := json.Unmarshal([]byte(data), ls); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, ls)
+	}
+
+	return sessions, rows.Err()
+}
+
+func (s *SQLStore) DeleteLoginSession(ctx context.Context, id string) error {
+	return s.exec(ctx, `DELETE FROM login_sessions WHERE id = ?`, id)
+}
+
+func (s *SQLStore) DeleteUserLoginSessions(ctx context.Context, userID string) error {
+	return s.exec(ctx, `DELETE FROM login_sessions WHERE user_id = ?`, userID)
+}
+
+func (s *SQLStore) AcquireLease(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+
+	// A single statement so that two instances cannot both take the lease: the existing row is only overwritten when it is ours or has expired
+	result, err := s.DB.ExecContext(ctx, s.rebind(`INSERT INTO leases (name, owner, expires) VALUES (?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET owner = excluded.owner, expires = excluded.expires
+		WHERE leases.owner = excluded.owner OR leases.expires < ?`),
+		name, owner, now.Add(ttl).UnixNano(), now.UnixNano())
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+func (s *SQLStore) ReleaseLease(ctx context.Context, name, owner string) error {
+	return s.exec(ctx, `DELETE FROM leases WHERE name = ? AND owner = ?`, name, owner)
+}
+
+func (s *SQLStore) SaveRoundTrip(ctx context.Context, rt *RoundTrip) error {
+	data, err := json.Marshal(rt)
+	if err != nil {
+		return err
+	}
+
+	return s.exec(ctx, `INSERT INTO round_trips (id, user_id, state, created, data) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET user_id = excluded.user_id, state = excluded.state, data = excluded.data`,
+		rt.ID, rt.UserID, rt.State, rt.Created.UnixNano(), string(data))
+}
+
+func (s *SQLStore) GetRoundTrip(ctx context.Context, id string) (*RoundTrip, error) {
+	rt := &RoundTrip{}
+	if err := s.getJSON(ctx, rt, "Round-trip", id, `SELECT data FROM round_trips WHERE id = ?`, id); err != nil {
+		return nil, err
+	}
+
+	return rt, nil
+}
+
+func (s *SQLStore) ListRoundTrips(ctx context.Context) ([]*RoundTrip, error) {
+	return s.listRoundTrips(ctx, `SELECT data FROM round_trips ORDER BY created`)
+}
+
+func (s *SQLStore) ListUserRoundTrips(ctx context.Context, userID string) ([]*RoundTrip, error) {
+	return s.listRoundTrips(ctx, `SELECT data FROM round_trips WHERE user_id = ? ORDER BY created`, userID)
+}
+
+func (s *SQLStore) listRoundTrips(ctx context.Context, query string, args ...interface{}) ([]*RoundTrip, error) {
+	rows, err := s.DB.QueryContext(ctx, s.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roundTrips := []*RoundTrip{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+
+		rt := &RoundTrip{}
+		if err := json.Unmarshal([]byte(data), rt); err != nil {
+			return nil, err
+		}
+		roundTrips = append(roundTrips, rt)
+	}
+
+	return roundTrips, rows.Err()
+}
+
+func (s *SQLStore) DeleteRoundTrip(ctx context.Context, id string) error {
+	return s.exec(ctx, `DELETE FROM round_trips WHERE id = ?`, id)
+}
+
+func (s *SQLStore) GetUserPrefs(ctx context.Context, userID string) (*UserPrefs, error) {
+	prefs := &UserPrefs{}
+	err := s.getJSON(ctx, prefs, "Preferences", userID, `SELECT data FROM user_prefs WHERE user_id = ?`, userID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	return prefs, nil
+}
+
+func (s *SQLStore) SaveUserPrefs(ctx context.Context, userID string, prefs *UserPrefs) error {
+	data, err := json.Marshal(prefs)
+	if err != nil {
+		return err
+	}
+
+	return s.exec(ctx, `INSERT INTO user_prefs (user_id, data) VALUES (?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET data = excluded.data`, userID, string(data))
+}
+
+func (s *SQLStore) ListUserPrefs(ctx context.Context) (map[string]*UserPrefs, error) {
+	rows, err := s.DB.QueryContext(ctx, `SELECT user_id, data FROM user_prefs`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prefs := map[string]*UserPrefs{}
+	for rows.Next() {
+		var userID, data string
+		if err := rows.Scan(&userID, &data); err != nil {
+			return nil, err
+		}
+
+		p := &UserPrefs{}
+		if err := json.Unmarshal([]byte(data), p); err != nil {
+			return nil, err
+		}
+		prefs[userID] = p
+	}
+
+	return prefs, rows.Err()
+}
@@ -0,0 +1,65 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package studio
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+// Digest is the size and checksums of file contents, which Studio and the storage check uploads against
+type Digest struct {
+	Size int64
+	// CRC is the CRC-32 (IEEE) of the contents in hex, as given to Studio in ProjectFilesRequest
+	CRC string
+	// MD5 is sent to the storage as the Content-MD5 of an upload
+	MD5 []byte
+}
+
+// ContentMD5 is the value of the Content-MD5 header for the contents
+func (d *Digest) ContentMD5() string {
+	return base64.StdEncoding.EncodeToString(d.MD5)
+}
+
+// Digester works out the Digest of everything written to it, so contents can be checked as they are streamed somewhere else
+type Digester struct {
+	size int64
+	crc  hash.Hash32
+	md5  hash.Hash
+}
+
+// NewDigester creates a Digester for contents yet to be written
+func NewDigester() *Digester {
+	return &Digester{crc: crc32.NewIEEE(), md5: md5.New()}
+}
+
+func (d *Digester) Write(p []byte) (int, error) {
+	d.crc.Write(p)
+	d.md5.Write(p)
+	d.size += int64(len(p))
+	return len(p), nil
+}
+
+// Digest is the digest of what was written so far
+func (d *Digester) Digest() *Digest {
+	return &Digest{
+		Size: d.size,
+		CRC:  fmt.Sprintf("%08x", d.crc.Sum32()),
+		MD5:  d.md5.Sum(nil),
+	}
+}
+
+// NewDigest reads r to the end and returns the digest of its contents
+func NewDigest(r io.Reader) (*Digest, error) {
+	d := NewDigester()
+	if _, err := io.Copy(d, r); err != nil {
+		return nil, err
+	}
+	return d.Digest(), nil
+}
@@ -0,0 +1,167 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package studio
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// Conditions an APIError can be matched against with errors.Is
+var (
+	ErrNotFound          = errors.New("studio: not found")
+	ErrSessionNotFound   = errors.New("studio: session not found")
+	ErrAlreadyCheckedOut = errors.New("studio: file already checked out")
+	ErrNotCheckedOut     = errors.New("studio: file not checked out")
+	ErrUnauthorized      = errors.New("studio: unauthorized")
+	ErrForbidden         = errors.New("studio: forbidden")
+)
+
+// APIError is a call to Studio, or to the storage behind its upload and download URLs, that failed with an error status. It is parsed from the error JSON Studio answers with.
+type APIError struct {
+	StatusCode int
+	// Code and Message are the error code and message given by Studio. Code is empty when Studio sends none.
+	Code    string
+	Message string
+	// RequestID identifies the call in Studio's logs, when Studio sends one
+	RequestID string
+	// Method and Endpoint are the call that failed. Endpoint is the path relative to the API, without the query, e.g. "projects/123/files/456/checkin".
+	Method   string
+	Endpoint string
+	// Retryable is set when the failure may go away by itself, like a 429 or a 503
+	Retryable bool
+}
+
+func (e *APIError) Error() string {
+	s := fmt.Sprintf("studio: %s %s: %d %s", e.Method, e.Endpoint, e.StatusCode, e.Message)
+	if e.Code != "" {
+		s += " (code " + e.Code + ")"
+	}
+	if e.RequestID != "" {
+		s += " (request " + e.RequestID + ")"
+	}
+	return s
+}
+
+// Is matches the error against the conditions of the package. Studio has no code for most of them, so they are worked out from the status and the call that failed; the message is meant for people and is never matched.
+func (e *APIError) Is(target error) bool {
+	conflict := e.StatusCode == http.StatusConflict || e.StatusCode == http.StatusPreconditionFailed
+
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrSessionNotFound:
+		return e.StatusCode == http.StatusNotFound && sessionEndpoint(e.Endpoint)
+	case ErrAlreadyCheckedOut:
+		return conflict && strings.HasSuffix(e.Endpoint, "/checkout-to-session")
+	case ErrNotCheckedOut:
+		return conflict && (strings.HasSuffix(e.Endpoint, "/undo-checkout") || strings.HasSuffix(e.Endpoint, "/checkin"))
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrUnavailable:
+		return e.StatusCode == http.StatusBadGateway || e.StatusCode == http.StatusServiceUnavailable || e.StatusCode == http.StatusGatewayTimeout
+	}
+	return false
+}
+
+// sessionEndpoint reports whether an endpoint is about a Session itself, like "sessions/123" or "sessions/123/users", rather than one of its files
+func sessionEndpoint(endpoint string) bool {
+	parts := strings.Split(endpoint, "/")
+	return len(parts) >= 2 && parts[0] == "sessions" && parts[1] != "" && (len(parts) == 2 || parts[2] != "files")
+}
+
+// errorBody holds the fields of the error bodies seen from Studio and from the storage. Studio sends JSON and the storage sends XML.
+type errorBody struct {
+	Message          string          `json:"Message" xml:"Message"`
+	ExceptionMessage string          `json:"ExceptionMessage" xml:"-"`
+	ErrorCode        json.RawMessage `json:"ErrorCode" xml:"-"`
+	Code             json.RawMessage `json:"Code" xml:"-"`
+	XMLCode          string          `json:"-" xml:"Code"`
+	RequestID        string          `json:"RequestId" xml:"RequestId"`
+	// OAuth style errors
+	Error            string `json:"error" xml:"-"`
+	ErrorDescription string `json:"error_description" xml:"-"`
+}
+
+// maxErrorBody is the most of an error response that is read
+const maxErrorBody = 64 << 10
+
+// CheckResponse returns an *APIError when resp has a failing status code
+func CheckResponse(resp *http.Response) error {
+	if resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  requestID(resp.Header),
+		Retryable:  retryableStatus(resp.StatusCode),
+	}
+	if resp.Request != nil {
+		apiErr.Method = resp.Request.Method
+		apiErr.Endpoint = strings.TrimPrefix(resp.Request.URL.Path, "/")
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	if err != nil {
+		return err
+	}
+
+	body := &errorBody{}
+	if json.Unmarshal(data, body) != nil && xml.Unmarshal(data, body) != nil {
+		body = &errorBody{}
+	}
+
+	apiErr.Message = firstOf(body.Message, body.ErrorDescription, body.ExceptionMessage, body.Error, http.StatusText(resp.StatusCode))
+	apiErr.Code = firstOf(rawCode(body.ErrorCode), rawCode(body.Code), body.XMLCode)
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = body.RequestID
+	}
+
+	return apiErr
+}
+
+// requestID finds the id of a request among the headers Studio and S3 use for it
+func requestID(h http.Header) string {
+	return firstOf(h.Get("X-Request-Id"), h.Get("Request-Id"), h.Get("X-Amz-Request-Id"))
+}
+
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// rawCode turns an error code sent as either a string or a number into a string
+func rawCode(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n.String()
+	}
+	return ""
+}
+
+func firstOf(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
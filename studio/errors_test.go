@@ -0,0 +1,43 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package studio
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	tests := []struct {
+		status   int
+		endpoint string
+		message  string
+		target   error
+		want     bool
+	}{
+		{http.StatusNotFound, "projects/1/files/2", "Project file not found.", ErrNotFound, true},
+		{http.StatusNotFound, "sessions/123", "Session not found.", ErrSessionNotFound, true},
+		{http.StatusNotFound, "sessions/123/users", "Not found.", ErrSessionNotFound, true},
+		{http.StatusNotFound, "sessions/123/files/4/snapshot", "Session not found.", ErrSessionNotFound, false},
+		{http.StatusConflict, "projects/1/files/2/checkout-to-session", "Conflict", ErrAlreadyCheckedOut, true},
+		{http.StatusPreconditionFailed, "projects/1/files/2/checkout-to-session", "", ErrAlreadyCheckedOut, true},
+		{http.StatusConflict, "projects/1/files/2/undo-checkout", "", ErrNotCheckedOut, true},
+		{http.StatusConflict, "projects/1/files/2/checkin", "", ErrNotCheckedOut, true},
+		// The message is never matched
+		{http.StatusBadRequest, "projects/1/files/2/checkout-to-session", "The file is already checked out.", ErrAlreadyCheckedOut, false},
+		{http.StatusBadRequest, "projects/1/files/2/undo-checkout", "The file is not checked out.", ErrNotCheckedOut, false},
+		{http.StatusConflict, "projects/1/files/2/confirm-upload", "The file is already checked out.", ErrAlreadyCheckedOut, false},
+		{http.StatusBadRequest, "projects/1/files/2", "Not found", ErrNotFound, false},
+		{http.StatusServiceUnavailable, "sessions", "", ErrUnavailable, true},
+	}
+
+	for _, test := range tests {
+		err := error(&APIError{StatusCode: test.status, Endpoint: test.endpoint, Message: test.message})
+		if got := errors.Is(err, test.target); got != test.want {
+			t.Errorf("%d %s %q is %v: %v, want %v", test.status, test.endpoint, test.message, test.target, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package studio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RootFolderID identifies the root folder of a Project
+const RootFolderID = 0
+
+type ProjectFolder struct {
+	ID             int    `json:"Id"`
+	Name           string `json:"Name"`
+	Path           string `json:"Path"`
+	ParentFolderID int    `json:"ParentFolderId"`
+}
+
+type ProjectFoldersResponse struct {
+	ProjectFolders []*ProjectFolder
+	TotalCount     int
+}
+
+type CreateProjectFolder struct {
+	Name           string `json:"Name"`
+	ParentFolderID int    `json:"ParentFolderId"`
+	Comment        string `json:"Comment,omitempty"`
+}
+
+type CreateProjectFolderResponse struct {
+	ID int `json:"Id"`
+}
+
+// FolderNode is a folder in the tree built by FolderTree
+type FolderNode struct {
+	*ProjectFolder
+	Children []*FolderNode
+}
+
+// Walk calls fn for the node and all of its descendants, depth first. depth is 0 for the node Walk is called on.
+func (n *FolderNode) Walk(fn func(node *FolderNode, depth int)) {
+	n.walk(fn, 0)
+}
+
+func (n *FolderNode) walk(fn func(node *FolderNode, depth int), depth int) {
+	fn(n, depth)
+	for _, c := range n.Children {
+		c.walk(fn, depth+1)
+	}
+}
+
+// ListFolders returns a page of the folders in a Project. The folders from every level of the Project are included.
+func (s *ProjectsService) ListFolders(ctx context.Context, projectID string, opts *ListOptions) (*ProjectFoldersResponse, error) {
+	req, err := s.client.NewRequest(ctx, "GET", opts.query(fmt.Sprintf("projects/%s/folders", projectID)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ProjectFoldersResponse{}
+	if err := s.client.Do(req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// FolderTree fetches every folder in a Project and arranges them under the root folder. Siblings are sorted by name.
+func (s *ProjectsService) FolderTree(ctx context.Context, projectID string) (*FolderNode, error) {
+	var folders []*ProjectFolder
+	for {
+		resp, err := s.ListFolders(ctx, projectID, &ListOptions{Skip: len(folders), Take: DefaultPageSize})
+		if err != nil {
+			return nil, err
+		}
+		folders = append(folders, resp.ProjectFolders...)
+
+		if len(resp.ProjectFolders) == 0 || len(folders) >= resp.TotalCount {
+			break
+		}
+	}
+
+	root := &FolderNode{ProjectFolder: &ProjectFolder{ID: RootFolderID, Name: "/", Path: "/"}}
+	nodes := map[int]*FolderNode{RootFolderID: root}
+	for _, f := range folders {
+		if f.ID != RootFolderID {
+			nodes[f.ID] = &FolderNode{ProjectFolder: f}
+		}
+	}
+
+	for _, f := range folders {
+		if f.ID == RootFolderID {
+			continue
+		}
+		parent, ok := nodes[f.ParentFolderID]
+		if !ok || f.ParentFolderID == f.ID {
+			// Orphaned folders are attached to the root so they can still be picked
+			parent = root
+		}
+		parent.Children = append(parent.Children, nodes[f.ID])
+	}
+
+	root.Walk(func(n *FolderNode, depth int) {
+		sort.Slice(n.Children, func(i, j int) bool {
+			return strings.ToLower(n.Children[i].Name) < strings.ToLower(n.Children[j].Name)
+		})
+	})
+
+	return root, nil
+}
+
+// CreateFolder makes a new folder in a Project
+func (s *ProjectsService) CreateFolder(ctx context.Context, projectID string, folder *CreateProjectFolder) (*CreateProjectFolderResponse, error) {
+	req, err := s.client.NewRequest(ctx, "POST", fmt.Sprintf("projects/%s/folders", projectID), folder)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CreateProjectFolderResponse{}
+	if err := s.client.Do(req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// DeleteFolder removes a folder, and everything in it, from a Project
+func (s *ProjectsService) DeleteFolder(ctx context.Context, projectID string, folderID int) error {
+	req, err := s.client.NewRequest(ctx, "DELETE", fmt.Sprintf("projects/%s/folders/%v", projectID, folderID), nil)
+	if err != nil {
+		return err
+	}
+
+	return s.client.Do(req, nil)
+}
@@ -0,0 +1,60 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package studio
+
+import (
+	"context"
+	"fmt"
+)
+
+type JobFlattenOptions struct {
+	Image             bool `json:"Image"`
+	Ellipse           bool `json:"Ellipse"`
+	Stamp             bool `json:"Stamp"`
+	Snapshot          bool `json:"Snapshot"`
+	TextAndCallout    bool `json:"TextAndCallout"`
+	InkAndHighlighter bool `json:"InkAndHighlighter"`
+	LineAndDimension  bool `json:"LineAndDimension"`
+	MeasureArea       bool `json:"MeasureArea"`
+	Polyline          bool `json:"Polyline"`
+	PolygonAndCloud   bool `json:"PolygonAndCloud"`
+	Rectangle         bool `json:"Rectangle"`
+	TextMarkups       bool `json:"TextMarkups"`
+	Group             bool `json:"Group"`
+	FileAttachment    bool `json:"FileAttachment"`
+	Flags             bool `json:"Flags"`
+	Notes             bool `json:"Notes"`
+	FormFields        bool `json:"FormFields"`
+}
+
+type JobFlatten struct {
+	Recoverable     bool              `json:"Recoverable"`
+	PageRange       string            `json:"PageRange,omitempty"`
+	LayerName       string            `json:"LayerName,omitempty"`
+	Options         JobFlattenOptions `json:"Options"`
+	CurrentPassword string            `json:"CurrentPassword,omitempty"`
+	OutputPath      string            `json:"OutputPath,omitempty"`
+	OutputFileName  string            `json:"OutputFileName,omitempty"`
+	Priority        int               `json:"Priority"`
+}
+
+type JobFlattenResponse struct {
+	ID int `json:"Id"`
+}
+
+// Flatten starts a job to flatten the markups of a project file
+func (s *JobsService) Flatten(ctx context.Context, projectID string, fileID int, job *JobFlatten) (*JobFlattenResponse, error) {
+	req, err := s.client.NewRequest(ctx, "POST", fmt.Sprintf("projects/%s/files/%v/jobs/flatten", projectID, fileID), job)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &JobFlattenResponse{}
+	if err := s.client.Do(req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
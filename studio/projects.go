@@ -0,0 +1,292 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package studio
+
+import (
+	"context"
+	"fmt"
+)
+
+type Project struct {
+	Name string `json:"Name"`
+	ID   string `json:"Id"`
+}
+
+type ProjectsResponse struct {
+	Projects   []*Project
+	TotalCount int
+}
+
+type ProjectFilesRequest struct {
+	Name           string `json:"Name"`
+	ParentFolderID int    `json:"ProjectFolderId"`
+	Size           int    `json:"Size"`
+	CRC            string `json:"CRC"`
+}
+
+type ProjectFilesResponse struct {
+	ID                int    `json:"Id"`
+	UploadUrl         string `json:"UploadUrl"`
+	UploadContentType string `json:"UploadContentType"`
+}
+
+type ProjectFile struct {
+	ID              int    `json:"Id"`
+	Name            string `json:"Name"`
+	ProjectFolderID int    `json:"ProjectFolderId"`
+	Size            int64  `json:"Size"`
+	CRC             string `json:"CRC"`
+	CheckedOut      bool   `json:"CheckedOut"`
+}
+
+type ProjectFilesListResponse struct {
+	ProjectFiles []*ProjectFile
+	TotalCount   int
+}
+
+type CheckoutToSession struct {
+	SessionID string `json:"SessionId"`
+}
+
+type CheckoutToSessionResponse struct {
+	SessionID string `json:"SessionId"`
+	ID        int    `json:"Id"`
+}
+
+type CheckinFromSession struct {
+	Comment string `json:"Comment"`
+}
+
+// List returns a page of the Projects the user has access to. TotalCount is the number of Projects across all pages.
+func (s *ProjectsService) List(ctx context.Context, opts *ListOptions) (*ProjectsResponse, error) {
+	req, err := s.client.NewRequest(ctx, "GET", opts.query("projects"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ProjectsResponse{}
+	if err := s.client.Do(req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// ProjectIterator walks every Project the user has access to, fetching a page at a time
+type ProjectIterator struct {
+	s        *ProjectsService
+	pageSize int
+	skip     int
+	total    int
+	page     []*Project
+	current  *Project
+	err      error
+	done     bool
+}
+
+// DefaultPageSize is the number of items requested per page by the iterators
+const DefaultPageSize = 100
+
+// Iterate returns an iterator over all Projects. A pageSize of 0 uses DefaultPageSize.
+func (s *ProjectsService) Iterate(pageSize int) *ProjectIterator {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	return &ProjectIterator{s: s, pageSize: pageSize, total: -1}
+}
+
+// Next advances to the next Project, fetching the next page when needed. It returns false when there are no more Projects or an error occurred.
+func (it *ProjectIterator) Next(ctx context.Context) bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil || (it.total >= 0 && it.skip >= it.total) {
+			return false
+		}
+
+		resp, err := it.s.List(ctx, &ListOptions{Skip: it.skip, Take: it.pageSize})
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.total = resp.TotalCount
+		it.skip += len(resp.Projects)
+		it.page = resp.Projects
+
+		// Guard against a server that reports more Projects than it hands out
+		if len(resp.Projects) == 0 {
+			it.done = true
+		}
+	}
+
+	it.current, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Project returns the Project the iterator is positioned on
+func (it *ProjectIterator) Project() *Project {
+	return it.current
+}
+
+// TotalCount is the number of Projects reported by Studio, or -1 before the first page is fetched
+func (it *ProjectIterator) TotalCount() int {
+	return it.total
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *ProjectIterator) Err() error {
+	return it.err
+}
+
+// ListFiles returns a page of the files in a Project. The files from every folder of the Project are included.
+func (s *ProjectsService) ListFiles(ctx context.Context, projectID string, opts *ListOptions) (*ProjectFilesListResponse, error) {
+	req, err := s.client.NewRequest(ctx, "GET", opts.query(fmt.Sprintf("projects/%s/files", projectID)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ProjectFilesListResponse{}
+	if err := s.client.Do(req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// GetFile returns a single project file
+func (s *ProjectsService) GetFile(ctx context.Context, projectID string, fileID int) (*ProjectFile, error) {
+	req, err := s.client.NewRequest(ctx, "GET", fmt.Sprintf("projects/%s/files/%v", projectID, fileID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ProjectFile{}
+	if err := s.client.Do(req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// DeleteFile removes a file from a Project
+func (s *ProjectsService) DeleteFile(ctx context.Context, projectID string, fileID int) error {
+	req, err := s.client.NewRequest(ctx, "DELETE", fmt.Sprintf("projects/%s/files/%v", projectID, fileID), nil)
+	if err != nil {
+		return err
+	}
+
+	return s.client.Do(req, nil)
+}
+
+// AllFiles fetches every file in a Project, a page at a time
+func (s *ProjectsService) AllFiles(ctx context.Context, projectID string) ([]*ProjectFile, error) {
+	var files []*ProjectFile
+	for {
+		resp, err := s.ListFiles(ctx, projectID, &ListOptions{Skip: len(files), Take: DefaultPageSize})
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, resp.ProjectFiles...)
+
+		if len(resp.ProjectFiles) == 0 || len(files) >= resp.TotalCount {
+			break
+		}
+	}
+
+	return files, nil
+}
+
+// FolderFiles fetches every file in a Project and returns those directly inside the given folder
+func (s *ProjectsService) FolderFiles(ctx context.Context, projectID string, folderID int) ([]*ProjectFile, error) {
+	all, err := s.AllFiles(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*ProjectFile
+	for _, f := range all {
+		if f.ProjectFolderID == folderID {
+			files = append(files, f)
+		}
+	}
+
+	return files, nil
+}
+
+// StartFileUpload creates the project file and returns the URL its contents should be uploaded to
+func (s *ProjectsService) StartFileUpload(ctx context.Context, projectID string, projectFile *ProjectFilesRequest) (*ProjectFilesResponse, error) {
+	req, err := s.client.NewRequest(ctx, "POST", fmt.Sprintf("projects/%s/files", projectID), projectFile)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ProjectFilesResponse{}
+	if err := s.client.Do(req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// ConfirmUpload tells Studio the contents of a file started with StartFileUpload have been uploaded
+func (s *ProjectsService) ConfirmUpload(ctx context.Context, projectID string, projectFileID int) error {
+	req, err := s.client.NewRequest(ctx, "POST", fmt.Sprintf("projects/%s/files/%v/confirm-upload", projectID, projectFileID), nil)
+	if err != nil {
+		return err
+	}
+
+	return s.client.Do(req, nil)
+}
+
+// CheckoutToSession checks a project file out into a Session
+func (s *ProjectsService) CheckoutToSession(ctx context.Context, projectID string, fileID int, sessionID string) (*CheckoutToSessionResponse, error) {
+	req, err := s.client.NewRequest(ctx, "POST", fmt.Sprintf("projects/%s/files/%v/checkout-to-session", projectID, fileID), &CheckoutToSession{SessionID: sessionID})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CheckoutToSessionResponse{}
+	if err := s.client.Do(req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// UndoCheckout releases a project file checked out to a Session without checking in any changes
+func (s *ProjectsService) UndoCheckout(ctx context.Context, projectID string, fileID int) error {
+	req, err := s.client.NewRequest(ctx, "POST", fmt.Sprintf("projects/%s/files/%v/undo-checkout", projectID, fileID), nil)
+	if err != nil {
+		return err
+	}
+
+	return s.client.Do(req, nil)
+}
+
+// Checkin starts a checkin of a new revision and returns the URL the revision should be uploaded to
+func (s *ProjectsService) Checkin(ctx context.Context, projectID string, fileID int) (*ProjectFilesResponse, error) {
+	req, err := s.client.NewRequest(ctx, "POST", fmt.Sprintf("projects/%s/files/%v/checkin", projectID, fileID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ProjectFilesResponse{}
+	if err := s.client.Do(req, response); err != nil {
+		return nil, err
+	}
+
+	response.ID = fileID
+
+	return response, nil
+}
+
+// ConfirmCheckin tells Studio the revision started with Checkin has been uploaded
+func (s *ProjectsService) ConfirmCheckin(ctx context.Context, projectID string, fileID int, comment string) error {
+	req, err := s.client.NewRequest(ctx, "POST", fmt.Sprintf("projects/%s/files/%v/confirm-checkin", projectID, fileID), &CheckinFromSession{Comment: comment})
+	if err != nil {
+		return err
+	}
+
+	return s.client.Do(req, nil)
+}
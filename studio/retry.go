@@ -0,0 +1,289 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package studio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrUnavailable is returned without calling Studio while the circuit of a Breaker is open. An APIError for a 502, 503 or 504 matches it as well.
+var ErrUnavailable = errors.New("studio: Studio is unavailable")
+
+// RetryPolicy controls how calls that fail in a way that may go away are retried
+type RetryPolicy struct {
+	// MaxAttempts is the most times a request is sent, the first time included. Nothing is retried when it is 1 or less.
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry. It doubles for every retry after that up to MaxDelay, and the actual wait is a random duration up to it.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// MaxRetryAfter is the longest Retry-After that is waited out. A response asking for a longer wait is returned as it is.
+	MaxRetryAfter time.Duration
+}
+
+// DefaultRetryPolicy is used by a Client unless WithRetryPolicy says otherwise
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:   4,
+	BaseDelay:     500 * time.Millisecond,
+	MaxDelay:      8 * time.Second,
+	MaxRetryAfter: 30 * time.Second,
+}
+
+// WithRetryPolicy sets how the calls of the Client are retried
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// WithBreaker makes the API calls of the Client go through b. A Breaker is meant to be shared by all the Clients talking to the same Studio.
+func WithBreaker(b *Breaker) Option {
+	return func(c *Client) {
+		c.breaker = b
+	}
+}
+
+type idempotentKey struct{}
+
+// idempotent marks a request that may be sent twice without harm even though its method says otherwise, like starting a snapshot
+func idempotent(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), idempotentKey{}, true))
+}
+
+// isIdempotent reports whether a request may be retried when it is not known whether Studio got it
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	}
+	marked, _ := req.Context().Value(idempotentKey{}).(bool)
+	return marked
+}
+
+// retryTransport sends a request again after a failure that may go away.
+// 429 and 503 mean the request was turned away, so any request is retried. After a network error, a 502 or a 504 Studio may have acted on the request anyway, so only idempotent requests are retried.
+type retryTransport struct {
+	base    http.RoundTripper
+	policy  RetryPolicy
+	breaker *Breaker
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.breaker == nil {
+		return t.send(req)
+	}
+
+	trial, ok := t.breaker.allow()
+	if !ok {
+		closeRequestBody(req)
+		return nil, ErrUnavailable
+	}
+
+	// The breaker counts calls rather than attempts, so a call is judged by how its last attempt went
+	resp, err := t.send(req)
+	if req.Context().Err() != nil {
+		// A call given up by the caller says nothing about Studio
+		t.breaker.abandon(trial)
+	} else {
+		t.breaker.record(trial, !unavailable(resp, err))
+	}
+	return resp, err
+}
+
+// send makes the attempts of a call until one is not worth retrying
+func (t *retryTransport) send(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	for attempt := 1; ; attempt++ {
+		try := req
+		if attempt > 1 {
+			try = req.Clone(req.Context())
+			if req.Body != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				try.Body = body
+			}
+		}
+
+		resp, err := base.RoundTrip(try)
+
+		wait, retry := t.backoff(req, resp, err, attempt)
+		if !retry {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff decides whether the outcome of an attempt is retried, and how long to wait first
+func (t *retryTransport) backoff(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if attempt >= t.policy.MaxAttempts || req.Context().Err() != nil {
+		return 0, false
+	}
+	// A body that cannot be sent again rules out a retry
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return 0, false
+	}
+
+	switch {
+	case err != nil:
+		if !isIdempotent(req) {
+			return 0, false
+		}
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable:
+		if wait, ok := retryAfter(resp); ok {
+			return wait, wait <= t.policy.MaxRetryAfter
+		}
+	case resp.StatusCode == http.StatusBadGateway || resp.StatusCode == http.StatusGatewayTimeout:
+		if !isIdempotent(req) {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	// Full jitter keeps the clients that failed together from retrying together
+	max := t.policy.BaseDelay << uint(attempt-1)
+	if max > t.policy.MaxDelay || max <= 0 {
+		max = t.policy.MaxDelay
+	}
+	if max <= 0 {
+		return 0, true
+	}
+	return time.Duration(rand.Int63n(int64(max))), true
+}
+
+// retryAfter reads the Retry-After header, given either in seconds or as a date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// unavailable reports whether an attempt failed in a way that says Studio is down rather than that the request was wrong
+func unavailable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func closeRequestBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
+// Breaker is a circuit breaker for calls to Studio. Once Threshold calls in a row find Studio unavailable the circuit opens, and calls fail straight away with ErrUnavailable instead of each waiting out its retries.
+// After Cooldown a single trial call is let through: the circuit closes again if it succeeds and stays open for another Cooldown if it does not.
+type Breaker struct {
+	Threshold int
+	Cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	// openUntil is when the next trial call may be made while the circuit is open
+	openUntil time.Time
+	trial     bool
+}
+
+// NewBreaker creates a Breaker that opens after threshold failed calls in a row
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{Threshold: threshold, Cooldown: cooldown}
+}
+
+// State reports whether the circuit is open, and if so when a call to Studio will next be tried
+func (b *Breaker) State() (open bool, retryAt time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.Threshold {
+		return false, time.Time{}
+	}
+	return true, b.openUntil
+}
+
+// allow reports whether a call may go to Studio, and whether it is the trial call. While the circuit is open only the trial call may.
+func (b *Breaker) allow() (trial, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.Threshold {
+		return false, true
+	}
+	if b.trial || time.Now().Before(b.openUntil) {
+		return false, false
+	}
+	b.trial = true
+	return true, true
+}
+
+// record counts the outcome of a call. Only the trial call ends the trial, as calls let through before the circuit opened may still be finishing.
+func (b *Breaker) record(trial, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if trial {
+		b.trial = false
+	}
+	if ok {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.Threshold {
+		b.openUntil = time.Now().Add(b.Cooldown)
+	}
+}
+
+// abandon ends a call without counting it
+func (b *Breaker) abandon(trial bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if trial {
+		b.trial = false
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package studio
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// roundTripFunc answers the attempts of a retryTransport without a server
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// TestBreakerCountsCalls checks that a call retried until it gives up counts as one failure, not one per attempt
+func TestBreakerCountsCalls(t *testing.T) {
+	attempts := 0
+	down := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return nil, errors.New("connection refused")
+	})
+
+	breaker := NewBreaker(2, time.Hour)
+	transport := &retryTransport{base: down, policy: RetryPolicy{MaxAttempts: 4}, breaker: breaker}
+
+	req, _ := http.NewRequest("GET", "https://studio.example/publicapi/v1/sessions", nil)
+	if _, err := transport.RoundTrip(req); err == nil {
+		t.Fatal("The call succeeded while Studio was down")
+	}
+	if attempts != 4 {
+		t.Errorf("The call made %d attempts, want 4", attempts)
+	}
+	if open, _ := breaker.State(); open {
+		t.Fatal("One call opened the circuit")
+	}
+
+	transport.RoundTrip(req)
+	if open, _ := breaker.State(); !open {
+		t.Fatal("Two failed calls did not open the circuit")
+	}
+	if _, err := transport.RoundTrip(req); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("A call while the circuit is open returned %v, want ErrUnavailable", err)
+	}
+}
+
+// TestBreakerTrial checks that a call let through before the circuit opened does not end the trial when it finishes during it
+func TestBreakerTrial(t *testing.T) {
+	breaker := NewBreaker(1, time.Millisecond)
+
+	late, ok := breaker.allow()
+	if late || !ok {
+		t.Fatalf("A closed circuit returned trial %v, ok %v", late, ok)
+	}
+	breaker.record(false, false)
+	time.Sleep(5 * time.Millisecond)
+
+	trial, ok := breaker.allow()
+	if !trial || !ok {
+		t.Fatalf("The call after the cooldown returned trial %v, ok %v, want the trial", trial, ok)
+	}
+
+	// The call from before the circuit opened finishes while the trial is out
+	breaker.record(late, false)
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := breaker.allow(); ok {
+		t.Error("A second call was let through while the trial was out")
+	}
+
+	breaker.abandon(late)
+	if _, ok := breaker.allow(); ok {
+		t.Error("Abandoning another call ended the trial")
+	}
+
+	breaker.record(trial, true)
+	if open, _ := breaker.State(); open {
+		t.Error("The trial succeeded but the circuit stayed open")
+	}
+}
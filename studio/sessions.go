@@ -0,0 +1,252 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package studio
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type DefaultSessionPermissions struct {
+	Type  string `json:"Type"`
+	Allow string `json:"Allow"`
+}
+
+type CreateSession struct {
+	Name               string                      `json:"Name"`
+	Notification       bool                        `json:"Notification"`
+	Restricted         bool                        `json:"Restricted"`
+	SessionEndDate     time.Time                   `json:"SessionEndDate"`
+	DefaultPermissions []DefaultSessionPermissions `json:"DefaultPermissions"`
+}
+
+type Session struct {
+	Name           string `json:"Name,omitempty"`
+	Notification   *bool  `json:"Notification,omitempty"`
+	Restricted     *bool  `json:"Restricted,omitempty"`
+	SessionEndDate string `json:"SessionEndDate,omitempty"`
+	OwnerEmailOrID string `json:"OwnerEmailOrId,omitempty"`
+	Status         string `json:"Status,omitempty"`
+}
+
+type CreateSessionResponse struct {
+	ID string `json:"Id"`
+}
+
+type SessionResponse struct {
+	ID             string `json:"Id"`
+	Name           string `json:"Name"`
+	Restricted     bool   `json:"Restricted"`
+	ExpirationDate string `json:"ExpirationDate"`
+	SessionEndDate string `json:"SessionEndDate"`
+	Version        int    `json:"Version"`
+	Created        string `json:"Created"`
+	InviteURL      string `json:"InviteUrl"`
+	OwnerEmail     string `json:"OwnerEmail"`
+	Status         string `json:"Status"`
+}
+
+type SessionsResponse struct {
+	Sessions   []*SessionResponse
+	TotalCount int
+}
+
+type SessionFile struct {
+	ID       int    `json:"Id"`
+	Name     string `json:"Name"`
+	Size     int64  `json:"Size"`
+	Version  int    `json:"Version"`
+	Created  string `json:"Created"`
+	Modified string `json:"Modified"`
+}
+
+type SessionFilesResponse struct {
+	Files      []*SessionFile
+	TotalCount int
+}
+
+type SessionUser struct {
+	ID         int    `json:"Id"`
+	Email      string `json:"Email"`
+	Name       string `json:"Name"`
+	IsOnline   bool   `json:"IsOnline"`
+	LastSeen   string `json:"LastSeen"`
+	Restricted bool   `json:"Restricted"`
+}
+
+type SessionUsersResponse struct {
+	SessionUsers []*SessionUser
+	TotalCount   int
+}
+
+type SnapshotResponse struct {
+	Status           string `json:"Status"`
+	StatusTime       string `json:"StatusTime"`
+	LastSnapshotTime string `json:"StatusSnapshotTime"`
+	DownloadURL      string `json:"DownloadUrl"`
+}
+
+// Create makes a new Session
+func (s *SessionsService) Create(ctx context.Context, session *CreateSession) (*CreateSessionResponse, error) {
+	req, err := s.client.NewRequest(ctx, "POST", "sessions", session)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CreateSessionResponse{}
+	if err := s.client.Do(req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// List returns a page of the Sessions the user has access to, including those created outside of this client. TotalCount is the number of Sessions across all pages.
+func (s *SessionsService) List(ctx context.Context, opts *ListOptions) (*SessionsResponse, error) {
+	req, err := s.client.NewRequest(ctx, "GET", opts.query("sessions"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SessionsResponse{}
+	if err := s.client.Do(req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// All fetches every Session the user has access to, a page at a time
+func (s *SessionsService) All(ctx context.Context) ([]*SessionResponse, error) {
+	var sessions []*SessionResponse
+	for {
+		resp, err := s.List(ctx, &ListOptions{Skip: len(sessions), Take: DefaultPageSize})
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, resp.Sessions...)
+
+		if len(resp.Sessions) == 0 || len(sessions) >= resp.TotalCount {
+			break
+		}
+	}
+
+	return sessions, nil
+}
+
+// Get returns a Session
+func (s *SessionsService) Get(ctx context.Context, sessionID string) (*SessionResponse, error) {
+	req, err := s.client.NewRequest(ctx, "GET", fmt.Sprintf("sessions/%s", sessionID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SessionResponse{}
+	if err := s.client.Do(req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// ListFiles returns a page of the files in a Session. TotalCount is the number of files across all pages.
+func (s *SessionsService) ListFiles(ctx context.Context, sessionID string, opts *ListOptions) (*SessionFilesResponse, error) {
+	req, err := s.client.NewRequest(ctx, "GET", opts.query(fmt.Sprintf("sessions/%s/files", sessionID)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SessionFilesResponse{}
+	if err := s.client.Do(req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// AllFiles fetches every file in a Session, a page at a time
+func (s *SessionsService) AllFiles(ctx context.Context, sessionID string) ([]*SessionFile, error) {
+	var files []*SessionFile
+	for {
+		resp, err := s.ListFiles(ctx, sessionID, &ListOptions{Skip: len(files), Take: DefaultPageSize})
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, resp.Files...)
+
+		if len(resp.Files) == 0 || len(files) >= resp.TotalCount {
+			break
+		}
+	}
+
+	return files, nil
+}
+
+// ListUsers returns a page of the attendees of a Session. TotalCount is the number of attendees across all pages.
+func (s *SessionsService) ListUsers(ctx context.Context, sessionID string, opts *ListOptions) (*SessionUsersResponse, error) {
+	req, err := s.client.NewRequest(ctx, "GET", opts.query(fmt.Sprintf("sessions/%s/users", sessionID)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SessionUsersResponse{}
+	if err := s.client.Do(req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// SetStatus changes the status of a Session, e.g. to Finalizing to remove the attendees
+func (s *SessionsService) SetStatus(ctx context.Context, sessionID, status string) (*SessionResponse, error) {
+	req, err := s.client.NewRequest(ctx, "PUT", fmt.Sprintf("sessions/%s", sessionID), &Session{Status: status})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SessionResponse{}
+	if err := s.client.Do(req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Delete removes a Session
+func (s *SessionsService) Delete(ctx context.Context, sessionID string) error {
+	req, err := s.client.NewRequest(ctx, "DELETE", fmt.Sprintf("sessions/%s", sessionID), nil)
+	if err != nil {
+		return err
+	}
+
+	return s.client.Do(req, nil)
+}
+
+// StartSnapshot kicks off the generation of a snapshot of the file including its markups
+func (s *SessionsService) StartSnapshot(ctx context.Context, sessionID string, fileID int) error {
+	req, err := s.client.NewRequest(ctx, "POST", fmt.Sprintf("sessions/%s/files/%v/snapshot", sessionID, fileID), nil)
+	if err != nil {
+		return err
+	}
+
+	// Starting a snapshot again only restarts it, so the call is safe to retry
+	return s.client.Do(idempotent(req), nil)
+}
+
+// SnapshotStatus reports on a snapshot started by StartSnapshot. The DownloadURL is set once the Status is Complete.
+func (s *SessionsService) SnapshotStatus(ctx context.Context, sessionID string, fileID int) (*SnapshotResponse, error) {
+	req, err := s.client.NewRequest(ctx, "GET", fmt.Sprintf("sessions/%s/files/%v/snapshot", sessionID, fileID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SnapshotResponse{}
+	if err := s.client.Do(req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,38 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package studio
+
+import (
+	"context"
+	"fmt"
+)
+
+type ShareLink struct {
+	ProjectFileID     int    `json:"ProjectFileID"`
+	PasswordProtected bool   `json:"PasswordProtected"`
+	Password          string `json:"Password"`
+	Expires           string `json:"Expires"`
+	Flatten           bool   `json:"Flatten"`
+}
+
+type SharedLinkResponse struct {
+	ID        int    `json:"Id"`
+	ShareLink string `json:"ShareLink"`
+}
+
+// Create generates a shareable link to a project file
+func (s *SharedLinksService) Create(ctx context.Context, projectID string, link *ShareLink) (*SharedLinkResponse, error) {
+	req, err := s.client.NewRequest(ctx, "POST", fmt.Sprintf("projects/%s/sharedlinks", projectID), link)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SharedLinkResponse{}
+	if err := s.client.Do(req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
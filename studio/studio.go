@@ -0,0 +1,255 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+// Package studio is a client for the Bluebeam Studio Public API.
+package studio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// DefaultBaseURL is the production Studio Public API
+const DefaultBaseURL = "https://studioapi.bluebeam.com/publicapi/v1/"
+
+// Client talks to the Studio Public API. The API is split into services that mirror the Studio resources.
+type Client struct {
+	// BaseURL is the root of the API. It always ends with a trailing slash.
+	BaseURL *url.URL
+
+	client       *http.Client
+	uploadClient *http.Client
+	retry        RetryPolicy
+	breaker      *Breaker
+
+	Sessions    *SessionsService
+	Projects    *ProjectsService
+	Jobs        *JobsService
+	SharedLinks *SharedLinksService
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the client whose transport is used underneath the OAuth transport.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.client = hc
+	}
+}
+
+// WithUploadClient sets the client used for transfers to and from the upload URLs handed out by Studio. These URLs are pre-signed so the client must not send the Studio token.
+func WithUploadClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.uploadClient = hc
+	}
+}
+
+// ListOptions selects a page of a list endpoint
+type ListOptions struct {
+	Skip int
+	Take int
+}
+
+// query adds the paging parameters to an API path
+func (o *ListOptions) query(path string) string {
+	if o == nil {
+		return path
+	}
+
+	v := url.Values{}
+	if o.Skip > 0 {
+		v.Set("skip", strconv.Itoa(o.Skip))
+	}
+	if o.Take > 0 {
+		v.Set("take", strconv.Itoa(o.Take))
+	}
+	if len(v) == 0 {
+		return path
+	}
+
+	return path + "?" + v.Encode()
+}
+
+type service struct {
+	client *Client
+}
+
+// SessionsService handles the /sessions endpoints
+type SessionsService service
+
+// ProjectsService handles the /projects endpoints
+type ProjectsService service
+
+// JobsService handles the /projects/{id}/files/{id}/jobs endpoints
+type JobsService service
+
+// SharedLinksService handles the /projects/{id}/sharedlinks endpoints
+type SharedLinksService service
+
+// NewClient creates a Client for the API at baseURL. Every API request is authorized with a token from ts.
+func NewClient(baseURL string, ts oauth2.TokenSource, opts ...Option) (*Client, error) {
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{BaseURL: u, retry: DefaultRetryPolicy}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	base := http.DefaultClient
+	if c.client != nil {
+		base = c.client
+	}
+	// Retries happen underneath the OAuth transport so every attempt carries the same token
+	c.client = &http.Client{
+		Transport:     &oauth2.Transport{Source: ts, Base: &retryTransport{base: base.Transport, policy: c.retry, breaker: c.breaker}},
+		CheckRedirect: base.CheckRedirect,
+		Jar:           base.Jar,
+		Timeout:       base.Timeout,
+	}
+
+	// Transfers are retried as well, but the storage being down says nothing about Studio so they do not go through the breaker
+	upload := c.uploadClient
+	if upload == nil {
+		upload = &http.Client{}
+	}
+	c.uploadClient = &http.Client{
+		Transport:     &retryTransport{base: upload.Transport, policy: c.retry},
+		CheckRedirect: upload.CheckRedirect,
+		Jar:           upload.Jar,
+		Timeout:       upload.Timeout,
+	}
+
+	c.Sessions = &SessionsService{client: c}
+	c.Projects = &ProjectsService{client: c}
+	c.Jobs = &JobsService{client: c}
+	c.SharedLinks = &SharedLinksService{client: c}
+
+	return c, nil
+}
+
+// NewRequest creates an API request. path is resolved relative to BaseURL and body, if not nil, is sent as JSON.
+func (c *Client) NewRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
+	u, err := c.BaseURL.Parse(strings.TrimPrefix(path, "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	var r io.Reader
+	if body != nil {
+		b := new(bytes.Buffer)
+		if err := json.NewEncoder(b).Encode(body); err != nil {
+			return nil, err
+		}
+		r = b
+	}
+
+	req, err := http.NewRequest(method, u.String(), r)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if body != nil {
+		req.Header.Add("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
+
+// Do sends an API request and decodes the JSON response into v when v is not nil
+func (c *Client) Do(req *http.Request, v interface{}) error {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckResponse(resp); err != nil {
+		if apiErr, ok := err.(*APIError); ok {
+			apiErr.Endpoint = strings.TrimPrefix(apiErr.Endpoint, strings.TrimPrefix(c.BaseURL.Path, "/"))
+		}
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(v)
+	if err == io.EOF {
+		err = nil
+	}
+	return err
+}
+
+// Upload sends the file contents to an upload URL handed out by Studio. digest describes the contents, and the storage refuses an upload that does not match it. The upload can only be retried when file is an io.Seeker.
+func (c *Client) Upload(ctx context.Context, uploadURL, contentType string, file io.Reader, digest *Digest) error {
+	// The transport closes the body it is given, which must not close a file that may be sent again
+	req, err := http.NewRequest("PUT", uploadURL, ioutil.NopCloser(file))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.ContentLength = digest.Size
+	if seeker, ok := file.(io.Seeker); ok {
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		req.GetBody = func() (io.ReadCloser, error) {
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				return nil, err
+			}
+			return ioutil.NopCloser(file), nil
+		}
+	}
+	req.Header.Add("Content-Type", contentType)
+	req.Header.Add("x-amz-server-side-encryption", "AES256")
+	req.Header.Add("Content-MD5", digest.ContentMD5())
+
+	resp, err := c.uploadClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("upload: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return CheckResponse(resp)
+}
+
+// Download fetches a file from a download URL handed out by Studio. The caller must close the body.
+func (c *Client) Download(ctx context.Context, downloadURL string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", downloadURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := c.uploadClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := CheckResponse(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	return resp, nil
+}
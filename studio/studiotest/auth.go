@@ -0,0 +1,326 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package studiotest
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Paths of the auth server endpoints, the same as on the Studio auth server
+const (
+	AuthorizePath = "/auth/oauth/authorize"
+	TokenPath     = "/auth/token"
+	RevokePath    = "/auth/token/revoke"
+)
+
+// AuthServer is a fake of the Studio OAuth authorization server for development and tests. Anyone can log in as any user name, which the tokens carry in the userName field like Studio's do.
+// Like Studio, refresh tokens are single use: refreshing hands out a new refresh token and the old one stops working.
+type AuthServer struct {
+	// ClientID and ClientSecret are the credentials the client must present. Any client is accepted when ClientID is empty.
+	ClientID     string
+	ClientSecret string
+	// AccessTokenLifetime is how long an access token lasts, an hour when zero
+	AccessTokenLifetime time.Duration
+
+	mu     sync.Mutex
+	codes  map[string]*authCode
+	grants map[string]*grant
+	// access and refresh map tokens to the grant that issued them
+	access  map[string]*grant
+	refresh map[string]*grant
+	// spent holds the refresh tokens that were used already
+	spent map[string]bool
+
+	refreshes       int
+	reusedRefreshes int
+}
+
+type authCode struct {
+	userName      string
+	redirectURI   string
+	challenge     string
+	challengeType string
+	expires       time.Time
+}
+
+// grant is a user's authorization of the client. Revoking it ends every token issued under it.
+type grant struct {
+	id       string
+	userName string
+	revoked  bool
+	// accessExpires is when each access token of the grant expires
+	accessExpires map[string]time.Time
+}
+
+// NewAuthServer creates a fake auth server that accepts the given client
+func NewAuthServer(clientID, clientSecret string) *AuthServer {
+	return &AuthServer{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		codes:        map[string]*authCode{},
+		grants:       map[string]*grant{},
+		access:       map[string]*grant{},
+		refresh:      map[string]*grant{},
+		spent:        map[string]bool{},
+	}
+}
+
+// Refreshes is how many times a refresh token was exchanged successfully
+func (a *AuthServer) Refreshes() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.refreshes
+}
+
+// ReusedRefreshes is how many times a refresh token that was already spent was presented again. A client that coordinates its refreshes never does this.
+func (a *AuthServer) ReusedRefreshes() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.reusedRefreshes
+}
+
+// Valid reports whether an access token was issued by the server and is still good. It can be used as the Authenticate function of a fake Studio.
+func (a *AuthServer) Valid(accessToken string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	g, ok := a.access[accessToken]
+	return ok && !g.revoked && time.Now().Before(g.accessExpires[accessToken])
+}
+
+// ExpireAccessTokens makes every access token issued so far expire now, as if their lifetime had passed
+func (a *AuthServer) ExpireAccessTokens() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, g := range a.grants {
+		for token := range g.accessExpires {
+			g.accessExpires[token] = time.Now()
+		}
+	}
+}
+
+func (a *AuthServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case AuthorizePath:
+		a.serveAuthorize(w, r)
+	case TokenPath:
+		a.serveToken(w, r)
+	case RevokePath:
+		a.serveRevoke(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+var authorizePage = template.Must(template.New("authorize").Parse(`<html>
+    <head>
+        <meta charset="utf-8">
+        <title>Development Auth Server</title>
+    </head>
+    <body style="font-family: sans-serif; margin: 25px;">
+        <h1>Development Auth Server</h1>
+        <p>This server stands in for the Bluebeam authorization service. Log in as any user; no password is needed.</p>
+        <form method="post">
+            <input type="hidden" name="query" value="{{.}}">
+            <label>User name <input name="userName" value="demo@example.com"></label>
+            <input type="submit" value="Authorize">
+        </form>
+    </body>
+</html>`))
+
+// serveAuthorize asks for a user name and sends the user back to the client with an authorization code
+func (a *AuthServer) serveAuthorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if r.Method == "POST" {
+		query, _ = url.ParseQuery(r.PostFormValue("query"))
+	}
+
+	if query.Get("response_type") != "code" || (a.ClientID != "" && query.Get("client_id") != a.ClientID) {
+		http.Error(w, "unauthorized_client", http.StatusBadRequest)
+		return
+	}
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || !redirectURI.IsAbs() {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	if r.Method != "POST" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		authorizePage.Execute(w, query.Encode())
+		return
+	}
+
+	userName := strings.TrimSpace(r.PostFormValue("userName"))
+	back := redirectURI.Query()
+	back.Set("state", query.Get("state"))
+	if userName == "" {
+		back.Set("error", "access_denied")
+	} else {
+		code := newAuthToken()
+		a.mu.Lock()
+		a.codes[code] = &authCode{
+			userName:      userName,
+			redirectURI:   redirectURI.String(),
+			challenge:     query.Get("code_challenge"),
+			challengeType: query.Get("code_challenge_method"),
+			expires:       time.Now().Add(time.Minute),
+		}
+		a.mu.Unlock()
+		back.Set("code", code)
+	}
+	redirectURI.RawQuery = back.Encode()
+
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+// serveToken exchanges an authorization code or a refresh token for new tokens
+func (a *AuthServer) serveToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !a.authenticateClient(r) {
+		tokenError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch r.PostFormValue("grant_type") {
+	case "authorization_code":
+		code, ok := a.codes[r.PostFormValue("code")]
+		// Codes are single use whatever the outcome
+		delete(a.codes, r.PostFormValue("code"))
+		if !ok || time.Now().After(code.expires) || code.redirectURI != r.PostFormValue("redirect_uri") || !verifyChallenge(code, r.PostFormValue("code_verifier")) {
+			tokenError(w, http.StatusBadRequest, "invalid_grant")
+			return
+		}
+
+		g := &grant{id: newAuthToken(), userName: code.userName, accessExpires: map[string]time.Time{}}
+		a.grants[g.id] = g
+		a.issue(w, g)
+	case "refresh_token":
+		token := r.PostFormValue("refresh_token")
+		if a.spent[token] {
+			a.reusedRefreshes++
+			tokenError(w, http.StatusBadRequest, "invalid_grant")
+			return
+		}
+		g, ok := a.refresh[token]
+		if !ok || g.revoked {
+			tokenError(w, http.StatusBadRequest, "invalid_grant")
+			return
+		}
+
+		delete(a.refresh, token)
+		a.spent[token] = true
+		a.refreshes++
+		a.issue(w, g)
+	default:
+		tokenError(w, http.StatusBadRequest, "unsupported_grant_type")
+	}
+}
+
+// issue answers with a new access and refresh token under a grant
+func (a *AuthServer) issue(w http.ResponseWriter, g *grant) {
+	lifetime := a.AccessTokenLifetime
+	if lifetime == 0 {
+		lifetime = time.Hour
+	}
+
+	accessToken, refreshToken := newAuthToken(), newAuthToken()
+	a.access[accessToken] = g
+	a.refresh[refreshToken] = g
+	g.accessExpires[accessToken] = time.Now().Add(lifetime)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token":  accessToken,
+		"token_type":    "bearer",
+		"expires_in":    int(lifetime / time.Second),
+		"refresh_token": refreshToken,
+		"userName":      g.userName,
+	})
+}
+
+// serveRevoke revokes the grant behind a token (RFC 7009). Unknown tokens are not an error.
+func (a *AuthServer) serveRevoke(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !a.authenticateClient(r) {
+		tokenError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	token := r.PostFormValue("token")
+	if g, ok := a.refresh[token]; ok {
+		g.revoked = true
+	}
+	if g, ok := a.access[token]; ok {
+		g.revoked = true
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// authenticateClient checks the client credentials, which may come in the form like the Studio auth server expects or in the Authorization header
+func (a *AuthServer) authenticateClient(r *http.Request) bool {
+	if a.ClientID == "" {
+		return true
+	}
+
+	id, secret, ok := r.BasicAuth()
+	if !ok {
+		id, secret = r.PostFormValue("client_id"), r.PostFormValue("client_secret")
+	}
+	return id == a.ClientID && subtle.ConstantTimeCompare([]byte(secret), []byte(a.ClientSecret)) == 1
+}
+
+// verifyChallenge checks a PKCE code verifier against the challenge of the authorization, if it had one
+func verifyChallenge(code *authCode, verifier string) bool {
+	switch code.challengeType {
+	case "":
+		return code.challenge == "" || code.challenge == verifier
+	case "plain":
+		return code.challenge == verifier
+	case "S256":
+		sum := sha256.Sum256([]byte(verifier))
+		return base64.RawURLEncoding.EncodeToString(sum[:]) == code.challenge
+	}
+	return false
+}
+
+func tokenError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
+
+func newAuthToken() string {
+	b := make([]byte, 24)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
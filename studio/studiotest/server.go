@@ -0,0 +1,845 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+// Package studiotest is an in-memory fake of the parts of the Studio Public API used by the app, along with the S3 style storage its upload and download URLs point to. It is meant for end-to-end tests and for demos that run without a Studio account.
+package studiotest
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"hash/crc32"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"bluebeam/gosessionroundtripper/studio"
+)
+
+// APIPath is where the fake serves the Public API. A client for the fake uses the URL of the server followed by APIPath as its base URL.
+const APIPath = "/publicapi/v1/"
+
+// storagePath is where the fake serves the uploads and downloads it hands out URLs for
+const storagePath = "/s3/"
+
+// Server is a fake Studio. It keeps everything in memory and is safe for concurrent use.
+type Server struct {
+	// Authenticate checks the bearer token of an API request. Any token is accepted when it is nil.
+	Authenticate func(accessToken string) bool
+	// BaseURL is the URL the server is reached at, used in the links it hands out. When empty it is worked out from the Host of each request, assuming the server is mounted at the root.
+	BaseURL string
+	// SnapshotDelay is how long a snapshot stays in progress after it was started
+	SnapshotDelay time.Duration
+
+	mu       sync.Mutex
+	nextID   int
+	projects map[string]*project
+	sessions map[string]*session
+	// objects are the contents of the storage, by key. A key is created when a URL is handed out and filled in by the upload.
+	objects  map[string][]byte
+	failures []*failure
+	// down makes every API call fail, see SetDown
+	down bool
+	// requests numbers the API calls for their request ids
+	requests int
+	// cutDownloads is how many of the next downloads are cut short, see CutNextDownload
+	cutDownloads int
+}
+
+type project struct {
+	studio.Project
+	folders map[int]*studio.ProjectFolder
+	files   map[int]*file
+}
+
+type file struct {
+	studio.ProjectFile
+	content []byte
+	// confirmed is false until the upload of a new file is confirmed
+	confirmed bool
+	// uploadKey is the storage key of an upload or checkin waiting to be confirmed
+	uploadKey string
+	// sessionID and sessionFileID are where the file is checked out to
+	sessionID     string
+	sessionFileID int
+	// Revisions counts the checkins
+	revisions int
+}
+
+type session struct {
+	studio.SessionResponse
+	files map[int]*sessionFile
+}
+
+type sessionFile struct {
+	studio.SessionFile
+	content []byte
+	markups []byte
+	// snapshotStarted is zero until a snapshot is started
+	snapshotStarted time.Time
+}
+
+// failure makes the next request for a method and path fail
+type failure struct {
+	method string
+	path   string
+	status int
+}
+
+// NewServer creates an empty fake Studio
+func NewServer() *Server {
+	return &Server{
+		nextID:   100,
+		projects: map[string]*project{},
+		sessions: map[string]*session{},
+		objects:  map[string][]byte{},
+	}
+}
+
+// Seed adds a Project with a few folders and files for demos
+func (s *Server) Seed() {
+	projectID := s.AddProject("Demo Project")
+	drawings := s.AddFolder(projectID, studio.RootFolderID, "Drawings")
+	s.AddFolder(projectID, drawings, "Architectural")
+	s.AddFolder(projectID, studio.RootFolderID, "Specifications")
+
+	s.AddFile(projectID, drawings, "A-101 Floor Plan.pdf", samplePDF("A-101 Floor Plan"))
+	s.AddFile(projectID, drawings, "A-201 Elevations.pdf", samplePDF("A-201 Elevations"))
+	s.AddFile(projectID, studio.RootFolderID, "Project Schedule.pdf", samplePDF("Project Schedule"))
+}
+
+// samplePDF is the smallest thing that passes for a PDF
+func samplePDF(title string) []byte {
+	return []byte("%PDF-1.4\n% " + title + "\n%%EOF\n")
+}
+
+// AddProject creates a Project and returns its id
+func (s *Server) AddProject(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.newSessionID()
+	s.projects[id] = &project{
+		Project: studio.Project{ID: id, Name: name},
+		folders: map[int]*studio.ProjectFolder{},
+		files:   map[int]*file{},
+	}
+	return id
+}
+
+// AddFolder creates a folder in a Project and returns its id
+func (s *Server) AddFolder(projectID string, parentID int, name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.projects[projectID]
+	id := s.newID()
+	p.folders[id] = &studio.ProjectFolder{ID: id, Name: name, Path: s.folderPath(p, parentID) + name + "/", ParentFolderID: parentID}
+	return id
+}
+
+// AddFile creates a project file that is ready to use and returns its id
+func (s *Server) AddFile(projectID string, folderID int, name string, content []byte) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.projects[projectID]
+	id := s.newID()
+	p.files[id] = &file{
+		ProjectFile: studio.ProjectFile{ID: id, Name: name, ProjectFolderID: folderID, Size: int64(len(content)), CRC: crc(content)},
+		content:     content,
+		confirmed:   true,
+	}
+	return id
+}
+
+// File returns a project file and its contents. ok is false when the file does not exist or its upload has not been confirmed.
+func (s *Server) File(projectID string, fileID int) (f studio.ProjectFile, content []byte, revisions int, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pf := s.findFile(projectID, fileID)
+	if pf == nil || !pf.confirmed {
+		return studio.ProjectFile{}, nil, 0, false
+	}
+	return pf.ProjectFile, pf.content, pf.revisions, true
+}
+
+// Files returns the ids of the confirmed files in a Project
+func (s *Server) Files(projectID string) []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ids []int
+	if p, ok := s.projects[projectID]; ok {
+		for _, f := range s.sortedFiles(p) {
+			ids = append(ids, f.ID)
+		}
+	}
+	return ids
+}
+
+// Folder reports whether a folder exists in a Project
+func (s *Server) Folder(projectID string, folderID int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.projects[projectID]
+	if !ok {
+		return false
+	}
+	_, ok = p.folders[folderID]
+	return ok
+}
+
+// Session returns a Session
+func (s *Server) Session(sessionID string) (studio.SessionResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ss, ok := s.sessions[sessionID]
+	if !ok {
+		return studio.SessionResponse{}, false
+	}
+	return ss.SessionResponse, true
+}
+
+// Sessions returns the ids of every Session
+func (s *Server) Sessions() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ids []string
+	for id := range s.sessions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Markup adds markups to a file in a Session, as an attendee would. They end up in the snapshot of the file.
+func (s *Server) Markup(sessionID string, fileID int, markup string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ss, ok := s.sessions[sessionID]
+	if !ok {
+		return fmt.Errorf("Session %s not found", sessionID)
+	}
+	sf, ok := ss.files[fileID]
+	if !ok {
+		return fmt.Errorf("File %d not found in Session %s", fileID, sessionID)
+	}
+
+	sf.markups = append(sf.markups, []byte("% markup: "+markup+"\n")...)
+	sf.Version++
+	return nil
+}
+
+// FailNext makes the next request with the given method and API path, such as "sessions" or "projects/1/files/2/checkin", fail with status.
+// A 429 or 503 comes with a Retry-After of a second, as Studio sends when it turns a request away.
+func (s *Server) FailNext(method, path string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = append(s.failures, &failure{method: method, path: strings.Trim(path, "/"), status: status})
+}
+
+// CutNextDownload makes the next download from the storage stop halfway through, as when the connection drops
+func (s *Server) CutNextDownload() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cutDownloads++
+}
+
+// SetDown makes every API call fail with a 503 until it is called again with false, like Studio during an outage
+func (s *Server) SetDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.down = down
+}
+
+func (s *Server) newID() int {
+	s.nextID++
+	return s.nextID
+}
+
+// newSessionID makes an id in the style of Studio Session and Project ids
+func (s *Server) newSessionID() string {
+	n := s.newID()
+	return fmt.Sprintf("%03d-%03d-%03d", n/1000000%1000, n/1000%1000, n%1000)
+}
+
+func (s *Server) folderPath(p *project, folderID int) string {
+	if f, ok := p.folders[folderID]; ok {
+		return f.Path
+	}
+	return "/"
+}
+
+func (s *Server) findFile(projectID string, fileID int) *file {
+	p, ok := s.projects[projectID]
+	if !ok {
+		return nil
+	}
+	return p.files[fileID]
+}
+
+func (s *Server) sortedFiles(p *project) []*file {
+	var files []*file
+	for _, f := range p.files {
+		if f.confirmed {
+			files = append(files, f)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].ID < files[j].ID })
+	return files
+}
+
+// baseURL is the URL the links handed out start with
+func (s *Server) baseURL(r *http.Request) string {
+	if s.BaseURL != "" {
+		return strings.TrimSuffix(s.BaseURL, "/")
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// newObject reserves a storage key and returns the URL to upload to or download from
+func (s *Server) newObject(r *http.Request, prefix string, content []byte) (string, string) {
+	key := fmt.Sprintf("%s/%d", prefix, s.newID())
+	s.objects[key] = content
+	return key, s.baseURL(r) + storagePath + key
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.HasPrefix(r.URL.Path, storagePath):
+		s.serveStorage(w, r, strings.TrimPrefix(r.URL.Path, storagePath))
+	case strings.HasPrefix(r.URL.Path, APIPath):
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == r.Header.Get("Authorization") || (s.Authenticate != nil && !s.Authenticate(token)) {
+			writeError(w, http.StatusUnauthorized, "Authorization has been denied for this request.")
+			return
+		}
+		s.serveAPI(w, r, strings.Trim(strings.TrimPrefix(r.URL.Path, APIPath), "/"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveStorage stands in for the pre-signed S3 URLs. Only keys that were handed out can be written.
+func (s *Server) serveStorage(w http.ResponseWriter, r *http.Request, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	content, ok := s.objects[key]
+	if !ok {
+		writeStorageError(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		if s.cutDownloads > 0 {
+			// The server drops the connection when less than the Content-Length is written
+			s.cutDownloads--
+			content = content[:len(content)/2]
+		}
+		w.Write(content)
+	case "PUT":
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeStorageError(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+			return
+		}
+		if r.ContentLength >= 0 && int64(len(body)) != r.ContentLength {
+			writeStorageError(w, http.StatusBadRequest, "IncompleteBody", "You did not provide the number of bytes specified by the Content-Length HTTP header.")
+			return
+		}
+		if contentMD5 := r.Header.Get("Content-MD5"); contentMD5 != "" {
+			sum := md5.Sum(body)
+			if contentMD5 != base64.StdEncoding.EncodeToString(sum[:]) {
+				writeStorageError(w, http.StatusBadRequest, "BadDigest", "The Content-MD5 you specified did not match what we received.")
+				return
+			}
+		}
+		s.objects[key] = body
+		w.WriteHeader(http.StatusOK)
+	default:
+		writeStorageError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "The specified method is not allowed against this resource.")
+	}
+}
+
+func (s *Server) serveAPI(w http.ResponseWriter, r *http.Request, path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+	w.Header().Set("X-Request-Id", fmt.Sprintf("fake-%06d", s.requests))
+
+	if s.down {
+		writeError(w, http.StatusServiceUnavailable, "Service Unavailable")
+		return
+	}
+
+	for i, f := range s.failures {
+		if f.method == r.Method && f.path == path {
+			s.failures = append(s.failures[:i], s.failures[i+1:]...)
+			if f.status == http.StatusTooManyRequests || f.status == http.StatusServiceUnavailable {
+				w.Header().Set("Retry-After", "1")
+			}
+			writeError(w, f.status, "Injected failure")
+			return
+		}
+	}
+
+	parts := strings.Split(path, "/")
+	switch {
+	case parts[0] == "projects":
+		s.serveProjects(w, r, parts[1:])
+	case parts[0] == "sessions":
+		s.serveSessions(w, r, parts[1:])
+	default:
+		writeError(w, http.StatusNotFound, "No HTTP resource was found that matches the request URI.")
+	}
+}
+
+func (s *Server) serveProjects(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 0 {
+		if r.Method != "GET" {
+			writeError(w, http.StatusMethodNotAllowed, "The requested resource does not support this method.")
+			return
+		}
+		var projects []*studio.Project
+		for _, p := range s.projects {
+			projects = append(projects, &studio.Project{ID: p.ID, Name: p.Name})
+		}
+		sort.Slice(projects, func(i, j int) bool { return projects[i].ID < projects[j].ID })
+		skip, take := page(r, len(projects))
+		writeJSON(w, &studio.ProjectsResponse{Projects: projects[skip:take], TotalCount: len(projects)})
+		return
+	}
+
+	p, ok := s.projects[parts[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Project not found.")
+		return
+	}
+
+	switch {
+	case len(parts) >= 2 && parts[1] == "folders":
+		s.serveFolders(w, r, p, parts[2:])
+	case len(parts) >= 2 && parts[1] == "files":
+		s.serveFiles(w, r, p, parts[2:])
+	case len(parts) == 2 && parts[1] == "sharedlinks" && r.Method == "POST":
+		link := &studio.ShareLink{}
+		if !readJSON(w, r, link) {
+			return
+		}
+		if f, ok := p.files[link.ProjectFileID]; !ok || !f.confirmed {
+			writeError(w, http.StatusNotFound, "Project file not found.")
+			return
+		}
+		id := s.newID()
+		writeJSON(w, &studio.SharedLinkResponse{ID: id, ShareLink: fmt.Sprintf("%s/share/%d", s.baseURL(r), id)})
+	default:
+		writeError(w, http.StatusNotFound, "No HTTP resource was found that matches the request URI.")
+	}
+}
+
+func (s *Server) serveFolders(w http.ResponseWriter, r *http.Request, p *project, parts []string) {
+	switch {
+	case len(parts) == 0 && r.Method == "GET":
+		var folders []*studio.ProjectFolder
+		for _, f := range p.folders {
+			folders = append(folders, f)
+		}
+		sort.Slice(folders, func(i, j int) bool { return folders[i].ID < folders[j].ID })
+		skip, take := page(r, len(folders))
+		writeJSON(w, &studio.ProjectFoldersResponse{ProjectFolders: folders[skip:take], TotalCount: len(folders)})
+	case len(parts) == 0 && r.Method == "POST":
+		request := &studio.CreateProjectFolder{}
+		if !readJSON(w, r, request) {
+			return
+		}
+		if _, ok := p.folders[request.ParentFolderID]; !ok && request.ParentFolderID != studio.RootFolderID {
+			writeError(w, http.StatusNotFound, "Parent folder not found.")
+			return
+		}
+		if request.Name == "" || strings.ContainsAny(request.Name, `\/:*?"<>|`) {
+			writeError(w, http.StatusBadRequest, "The folder name is invalid.")
+			return
+		}
+		id := s.newID()
+		p.folders[id] = &studio.ProjectFolder{ID: id, Name: request.Name, Path: s.folderPath(p, request.ParentFolderID) + request.Name + "/", ParentFolderID: request.ParentFolderID}
+		writeJSON(w, &studio.CreateProjectFolderResponse{ID: id})
+	case len(parts) == 1 && r.Method == "DELETE":
+		id, _ := strconv.Atoi(parts[0])
+		if _, ok := p.folders[id]; !ok {
+			writeError(w, http.StatusNotFound, "Folder not found.")
+			return
+		}
+		s.deleteFolder(p, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotFound, "No HTTP resource was found that matches the request URI.")
+	}
+}
+
+// deleteFolder removes a folder along with its subfolders and files
+func (s *Server) deleteFolder(p *project, id int) {
+	for _, f := range p.folders {
+		if f.ParentFolderID == id && f.ID != id {
+			s.deleteFolder(p, f.ID)
+		}
+	}
+	for fileID, f := range p.files {
+		if f.ProjectFolderID == id {
+			delete(p.files, fileID)
+		}
+	}
+	delete(p.folders, id)
+}
+
+func (s *Server) serveFiles(w http.ResponseWriter, r *http.Request, p *project, parts []string) {
+	if len(parts) == 0 {
+		switch r.Method {
+		case "GET":
+			var files []*studio.ProjectFile
+			for _, f := range s.sortedFiles(p) {
+				pf := f.ProjectFile
+				files = append(files, &pf)
+			}
+			skip, take := page(r, len(files))
+			writeJSON(w, &studio.ProjectFilesListResponse{ProjectFiles: files[skip:take], TotalCount: len(files)})
+		case "POST":
+			request := &studio.ProjectFilesRequest{}
+			if !readJSON(w, r, request) {
+				return
+			}
+			if _, ok := p.folders[request.ParentFolderID]; !ok && request.ParentFolderID != studio.RootFolderID {
+				writeError(w, http.StatusNotFound, "Folder not found.")
+				return
+			}
+			id := s.newID()
+			key, uploadURL := s.newObject(r, "uploads", nil)
+			p.files[id] = &file{
+				ProjectFile: studio.ProjectFile{ID: id, Name: request.Name, ProjectFolderID: request.ParentFolderID, Size: int64(request.Size), CRC: request.CRC},
+				uploadKey:   key,
+			}
+			writeJSON(w, &studio.ProjectFilesResponse{ID: id, UploadUrl: uploadURL, UploadContentType: "application/pdf"})
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "The requested resource does not support this method.")
+		}
+		return
+	}
+
+	id, _ := strconv.Atoi(parts[0])
+	f, ok := p.files[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Project file not found.")
+		return
+	}
+
+	action := ""
+	if len(parts) > 1 {
+		action = strings.Join(parts[1:], "/")
+	}
+
+	switch {
+	case action == "" && r.Method == "GET":
+		if !f.confirmed {
+			writeError(w, http.StatusNotFound, "Project file not found.")
+			return
+		}
+		writeJSON(w, &f.ProjectFile)
+	case action == "" && r.Method == "DELETE":
+		if f.CheckedOut {
+			writeError(w, http.StatusConflict, "The file is checked out.")
+			return
+		}
+		delete(p.files, id)
+		w.WriteHeader(http.StatusNoContent)
+	case action == "confirm-upload" && r.Method == "POST":
+		if f.confirmed || f.uploadKey == "" {
+			writeError(w, http.StatusConflict, "The file has no upload to confirm.")
+			return
+		}
+		content := s.objects[f.uploadKey]
+		if content == nil {
+			writeError(w, http.StatusBadRequest, "The file has not been uploaded.")
+			return
+		}
+		if f.Size > 0 && f.Size != int64(len(content)) {
+			writeError(w, http.StatusBadRequest, "The uploaded file does not match the size given.")
+			return
+		}
+		if f.CRC != "" && !strings.EqualFold(f.CRC, crc(content)) {
+			writeError(w, http.StatusBadRequest, "The uploaded file does not match the CRC given.")
+			return
+		}
+		delete(s.objects, f.uploadKey)
+		f.content, f.Size, f.uploadKey, f.confirmed = content, int64(len(content)), "", true
+		w.WriteHeader(http.StatusNoContent)
+	case action == "checkout-to-session" && r.Method == "POST":
+		request := &studio.CheckoutToSession{}
+		if !readJSON(w, r, request) {
+			return
+		}
+		ss, ok := s.sessions[request.SessionID]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Session not found.")
+			return
+		}
+		if !f.confirmed {
+			writeError(w, http.StatusBadRequest, "The file has not been uploaded.")
+			return
+		}
+		if f.CheckedOut {
+			writeError(w, http.StatusConflict, "The file is already checked out.")
+			return
+		}
+		sessionFileID := s.newID()
+		now := time.Now().UTC().Format(time.RFC3339)
+		ss.files[sessionFileID] = &sessionFile{
+			SessionFile: studio.SessionFile{ID: sessionFileID, Name: f.Name, Size: int64(len(f.content)), Version: 1, Created: now, Modified: now},
+			content:     f.content,
+		}
+		f.CheckedOut, f.sessionID, f.sessionFileID = true, ss.ID, sessionFileID
+		writeJSON(w, &studio.CheckoutToSessionResponse{SessionID: ss.ID, ID: sessionFileID})
+	case action == "undo-checkout" && r.Method == "POST":
+		if !f.CheckedOut {
+			writeError(w, http.StatusConflict, "The file is not checked out.")
+			return
+		}
+		if ss, ok := s.sessions[f.sessionID]; ok {
+			delete(ss.files, f.sessionFileID)
+		}
+		f.CheckedOut, f.sessionID, f.sessionFileID, f.uploadKey = false, "", 0, ""
+		w.WriteHeader(http.StatusNoContent)
+	case action == "checkin" && r.Method == "POST":
+		if !f.CheckedOut {
+			writeError(w, http.StatusConflict, "The file is not checked out.")
+			return
+		}
+		key, uploadURL := s.newObject(r, "revisions", nil)
+		f.uploadKey = key
+		writeJSON(w, &studio.ProjectFilesResponse{UploadUrl: uploadURL, UploadContentType: "application/pdf"})
+	case action == "confirm-checkin" && r.Method == "POST":
+		if !f.CheckedOut || f.uploadKey == "" {
+			writeError(w, http.StatusConflict, "The file has no checkin to confirm.")
+			return
+		}
+		content := s.objects[f.uploadKey]
+		if content == nil {
+			writeError(w, http.StatusBadRequest, "The revision has not been uploaded.")
+			return
+		}
+		delete(s.objects, f.uploadKey)
+		f.content, f.Size, f.CRC, f.uploadKey = content, int64(len(content)), crc(content), ""
+		f.CheckedOut, f.sessionID, f.sessionFileID = false, "", 0
+		f.revisions++
+		w.WriteHeader(http.StatusNoContent)
+	case action == "jobs/flatten" && r.Method == "POST":
+		if !f.confirmed || f.CheckedOut {
+			writeError(w, http.StatusConflict, "The file cannot be flattened while it is checked out.")
+			return
+		}
+		writeJSON(w, &studio.JobFlattenResponse{ID: s.newID()})
+	default:
+		writeError(w, http.StatusNotFound, "No HTTP resource was found that matches the request URI.")
+	}
+}
+
+func (s *Server) serveSessions(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 0 {
+		switch r.Method {
+		case "GET":
+			var sessions []*studio.SessionResponse
+			for _, ss := range s.sessions {
+				sr := ss.SessionResponse
+				sessions = append(sessions, &sr)
+			}
+			sort.Slice(sessions, func(i, j int) bool { return sessions[i].ID < sessions[j].ID })
+			skip, take := page(r, len(sessions))
+			writeJSON(w, &studio.SessionsResponse{Sessions: sessions[skip:take], TotalCount: len(sessions)})
+		case "POST":
+			request := &studio.CreateSession{}
+			if !readJSON(w, r, request) {
+				return
+			}
+			if request.Name == "" {
+				writeError(w, http.StatusBadRequest, "The Session needs a name.")
+				return
+			}
+			id := s.newSessionID()
+			now := time.Now().UTC().Format(time.RFC3339)
+			s.sessions[id] = &session{
+				SessionResponse: studio.SessionResponse{
+					ID:             id,
+					Name:           request.Name,
+					Restricted:     request.Restricted,
+					SessionEndDate: request.SessionEndDate.UTC().Format(time.RFC3339),
+					Version:        1,
+					Created:        now,
+					InviteURL:      fmt.Sprintf("%s/join/%s", s.baseURL(r), id),
+					OwnerEmail:     "demo@example.com",
+					Status:         "Active",
+				},
+				files: map[int]*sessionFile{},
+			}
+			writeJSON(w, &studio.CreateSessionResponse{ID: id})
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "The requested resource does not support this method.")
+		}
+		return
+	}
+
+	ss, ok := s.sessions[parts[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Session not found.")
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == "GET":
+		writeJSON(w, &ss.SessionResponse)
+	case len(parts) == 1 && r.Method == "PUT":
+		request := &studio.Session{}
+		if !readJSON(w, r, request) {
+			return
+		}
+		if request.Name != "" {
+			ss.Name = request.Name
+		}
+		if request.Status != "" {
+			if request.Status != "Active" && request.Status != "Finalizing" {
+				writeError(w, http.StatusBadRequest, "The Session status is invalid.")
+				return
+			}
+			ss.Status = request.Status
+		}
+		ss.Version++
+		writeJSON(w, &ss.SessionResponse)
+	case len(parts) == 1 && r.Method == "DELETE":
+		delete(s.sessions, ss.ID)
+		w.WriteHeader(http.StatusNoContent)
+	case len(parts) == 2 && parts[1] == "files" && r.Method == "GET":
+		var files []*studio.SessionFile
+		for _, sf := range ss.files {
+			f := sf.SessionFile
+			files = append(files, &f)
+		}
+		sort.Slice(files, func(i, j int) bool { return files[i].ID < files[j].ID })
+		skip, take := page(r, len(files))
+		writeJSON(w, &studio.SessionFilesResponse{Files: files[skip:take], TotalCount: len(files)})
+	case len(parts) == 2 && parts[1] == "users" && r.Method == "GET":
+		users := []*studio.SessionUser{{ID: 1, Email: ss.OwnerEmail, Name: "Demo User", IsOnline: true, LastSeen: ss.Created}}
+		writeJSON(w, &studio.SessionUsersResponse{SessionUsers: users, TotalCount: len(users)})
+	case len(parts) == 4 && parts[1] == "files" && parts[3] == "snapshot":
+		id, _ := strconv.Atoi(parts[2])
+		sf, ok := ss.files[id]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Session file not found.")
+			return
+		}
+		s.serveSnapshot(w, r, sf)
+	default:
+		writeError(w, http.StatusNotFound, "No HTTP resource was found that matches the request URI.")
+	}
+}
+
+// serveSnapshot starts a snapshot, or reports on it. A snapshot is complete SnapshotDelay after it was started and holds the file with its markups.
+func (s *Server) serveSnapshot(w http.ResponseWriter, r *http.Request, sf *sessionFile) {
+	switch r.Method {
+	case "POST":
+		sf.snapshotStarted = time.Now()
+		w.WriteHeader(http.StatusNoContent)
+	case "GET":
+		if sf.snapshotStarted.IsZero() {
+			writeError(w, http.StatusNotFound, "No snapshot has been started.")
+			return
+		}
+
+		now := time.Now().UTC()
+		if now.Before(sf.snapshotStarted.Add(s.SnapshotDelay)) {
+			writeJSON(w, &studio.SnapshotResponse{Status: "InProgress", StatusTime: now.Format(time.RFC3339)})
+			return
+		}
+
+		content := append(append([]byte(nil), sf.content...), sf.markups...)
+		_, downloadURL := s.newObject(r, "snapshots", content)
+		writeJSON(w, &studio.SnapshotResponse{
+			Status:           "Complete",
+			StatusTime:       now.Format(time.RFC3339),
+			LastSnapshotTime: sf.snapshotStarted.UTC().Format(time.RFC3339),
+			DownloadURL:      downloadURL,
+		})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "The requested resource does not support this method.")
+	}
+}
+
+// page returns the bounds of the page selected by the skip and take parameters of a list of n items
+func page(r *http.Request, n int) (int, int) {
+	skip, _ := strconv.Atoi(r.URL.Query().Get("skip"))
+	take, _ := strconv.Atoi(r.URL.Query().Get("take"))
+	if skip < 0 || skip > n {
+		skip = n
+	}
+	if take <= 0 || skip+take > n {
+		return skip, n
+	}
+	return skip, skip + take
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "The request is invalid.")
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// crc is the CRC of file contents in the form Studio takes them
+func crc(content []byte) string {
+	return fmt.Sprintf("%08x", crc32.ChecksumIEEE(content))
+}
+
+// writeStorageError answers with an error body shaped like those of S3
+func writeStorageError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	xml.NewEncoder(w).Encode(struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string
+		Message string
+	}{Code: code, Message: message})
+}
+
+// writeError answers with an error body shaped like those of Studio
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"Message": message})
+}
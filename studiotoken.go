@@ -6,63 +6,159 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"golang.org/x/oauth2"
+	"golang.org/x/sync/singleflight"
 )
 
 // StudioConfig extends the oauth2.Config to get a hook for storing refreshed tokens
 type StudioConfig struct {
 	*oauth2.Config
+	// PKCE adds a code challenge to the authorization request and the matching verifier to the code exchange
+	PKCE bool
+	// RevokeURL is the token revocation endpoint of the auth server
+	RevokeURL string
+
+	// refreshes makes concurrent refreshes of a user's token in this process share one request
+	refreshes singleflight.Group
+}
+
+// NewVerifier returns a new PKCE code verifier for an authorization, or "" when PKCE is off
+func (c *StudioConfig) NewVerifier() string {
+	if !c.PKCE {
+		return ""
+	}
+	return oauth2.GenerateVerifier()
+}
+
+// AuthCodeURL returns the URL of the authorization page, with the S256 code challenge of verifier when one is given
+func (c *StudioConfig) AuthCodeURL(state, verifier string) string {
+	if verifier == "" {
+		return c.Config.AuthCodeURL(state)
+	}
+	return c.Config.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier))
 }
 
 // StoreToken is called whenever a new Token is received and when a Token is refreshed. Studio Tokens are refreshed regularly and are one time use. It is important to always store the latest token.
-func (c *StudioConfig) StoreToken(token *oauth2.Token) error {
-	userName := token.Extra("userName").(string)
-	fmt.Println("Saving Token: " + userName)
-	env.DataStore.StoreToken(userName, token)
-	return nil
+func (c *StudioConfig) StoreToken(ctx context.Context, userID string, token *oauth2.Token) error {
+	fmt.Println("Saving Token: " + userID)
+	return env.DataStore.StoreToken(ctx, userID, token)
 }
 
-func (c *StudioConfig) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
-	token, err := c.Config.Exchange(ctx, code)
+// Exchange trades an authorization code for a token and stores it. verifier is the one given to AuthCodeURL, if any.
+func (c *StudioConfig) Exchange(ctx context.Context, code, verifier string) (*oauth2.Token, error) {
+	var opts []oauth2.AuthCodeOption
+	if verifier != "" {
+		opts = append(opts, oauth2.VerifierOption(verifier))
+	}
+
+	token, err := c.Config.Exchange(ctx, code, opts...)
 	if err != nil {
 		return nil, err
 	}
-	if err := c.StoreToken(token); err != nil {
+	userName, err := tokenUserName(token)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.StoreToken(ctx, userName, token); err != nil {
 		return nil, err
 	}
 	return token, nil
 }
 
+// tokenUserName is the Studio user a token was issued to, sent by Studio alongside the token
+func tokenUserName(token *oauth2.Token) (string, error) {
+	name, ok := token.Extra("userName").(string)
+	if !ok || name == "" {
+		return "", errors.New("Authorization Error: Studio did not say which user signed in")
+	}
+	return name, nil
+}
+
+// Revoke asks the auth server to revoke the grant behind a token (RFC 7009). The refresh token is revoked when there is one, as that also ends the access tokens issued from it.
+func (c *StudioConfig) Revoke(ctx context.Context, token *oauth2.Token) error {
+	if c.RevokeURL == "" {
+		return nil
+	}
+
+	value, hint := token.RefreshToken, "refresh_token"
+	if value == "" {
+		value, hint = token.AccessToken, "access_token"
+	}
+
+	// Like the token endpoint, the Studio auth server expects the client credentials in the form rather than the Authorization header
+	form := url.Values{
+		"token":           {value},
+		"token_type_hint": {hint},
+		"client_id":       {c.ClientID},
+		"client_secret":   {c.ClientSecret},
+	}
+
+	req, err := http.NewRequest("POST", c.RevokeURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	// The same client as the token exchange is used, so oauth2.HTTPClient in the context applies here too
+	client := http.DefaultClient
+	if hc, ok := ctx.Value(oauth2.HTTPClient).(*http.Client); ok {
+		client = hc
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("Revoking token: %s %s", resp.Status, body)
+	}
+
+	return nil
+}
+
 // Client retrieves an http.Client that is all setup for providing OAuth tokens and refreshing them as needed
-func (c *StudioConfig) Client(ctx context.Context, t *oauth2.Token) *http.Client {
-	return oauth2.NewClient(ctx, c.TokenSource(ctx, t))
+func (c *StudioConfig) Client(ctx context.Context, userID string, t *oauth2.Token) *http.Client {
+	return oauth2.NewClient(ctx, c.TokenSource(ctx, userID, t))
 }
 
-// TokenSource creates a ReuseTokenSource that will call back to StoreToken when a refresh happens
-func (c *StudioConfig) TokenSource(ctx context.Context, t *oauth2.Token) oauth2.TokenSource {
+// TokenSource creates a ReuseTokenSource for a user's token. Refreshes are coordinated with every other TokenSource of the user so each refresh token is only spent once.
+func (c *StudioConfig) TokenSource(ctx context.Context, userID string, t *oauth2.Token) oauth2.TokenSource {
 	rts := &StudioTokenSource{
-		source: c.Config.TokenSource(ctx, t),
+		ctx:    ctx,
+		userID: userID,
+		token:  t,
 		config: c,
 	}
 	return oauth2.ReuseTokenSource(t, rts)
 }
 
-// StudioTokenStore extends the oauth2.TokenSource to provide a handle back to the StudioConfig so that StoreToken can be called
+// StudioTokenSource refreshes a user's token through StudioConfig.refresh so that the new token is stored and shared
 type StudioTokenSource struct {
-	source oauth2.TokenSource
+	ctx    context.Context
+	userID string
+	token  *oauth2.Token
 	config *StudioConfig
 }
 
 func (t *StudioTokenSource) Token() (*oauth2.Token, error) {
-	token, err := t.source.Token()
+	token, err := t.config.refresh(t.ctx, t.userID, t.token)
 	if err != nil {
 		return nil, err
 	}
-	if err := t.config.StoreToken(token); err != nil {
-		return nil, err
-	}
-	return token, nil
+	t.token = token
+	// The refreshed token is shared by every request that refreshed it at once, and the caller may change it
+	own := *token
+	return &own, nil
 }
@@ -0,0 +1,171 @@
+// Copyright (c) Bluebeam Inc. All rights reserved.
+//
+// Licensed under the MIT License. See LICENSE in the project root for license information.
+
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// tokenCipher encrypts stored tokens with envelope encryption. Every record gets its own random data key which encrypts the token; the data key is in turn encrypted with a master key and stored alongside it with the id of that master key.
+// Records are always sealed with the first master key and opened with whichever key they name, so master keys can be rotated by adding a new one first, re-encrypting the tokens and then removing the old one.
+// A nil tokenCipher stores tokens in plain text.
+type tokenCipher struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// tokenEnvelope is the stored form of an encrypted token
+type tokenEnvelope struct {
+	KeyID string `json:"keyId"`
+	// WrappedKey is the data key encrypted with the master key, nonce first
+	WrappedKey []byte `json:"wrappedKey"`
+	// Data is the token encrypted with the data key, nonce first
+	Data []byte `json:"data"`
+}
+
+// newTokenCipher parses master keys given as "id:base64 key", newest first. Keys must be 32 bytes for AES-256. No keys gives a nil tokenCipher.
+func newTokenCipher(keys []string) (*tokenCipher, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	c := &tokenCipher{keys: map[string]cipher.AEAD{}}
+	for _, k := range keys {
+		parts := strings.SplitN(k, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.New("Token keys must be given as id:base64 key")
+		}
+		id := parts[0]
+
+		key, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("Token key %s: %v", id, err)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("Token key %s must be 32 bytes", id)
+		}
+		if _, ok := c.keys[id]; ok {
+			return nil, fmt.Errorf("Token key %s is given twice", id)
+		}
+
+		aead, err := newGCM(key)
+		if err != nil {
+			return nil, err
+		}
+
+		c.keys[id] = aead
+		if c.current == "" {
+			c.current = id
+		}
+	}
+
+	return c, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Seal encrypts the token record of a user. The user id is bound to the record so it cannot be copied to another user.
+func (c *tokenCipher) Seal(userID string, plaintext []byte) ([]byte, error) {
+	if c == nil {
+		return plaintext, nil
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+
+	data, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope := &tokenEnvelope{KeyID: c.current}
+
+	envelope.Data, err = seal(data, plaintext, []byte(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	envelope.WrappedKey, err = seal(c.keys[c.current], dataKey, []byte(c.current+"\x00"+userID))
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(envelope)
+}
+
+// Open decrypts a record sealed by Seal. Records stored before encryption was turned on are returned as they are.
+func (c *tokenCipher) Open(userID string, record []byte) ([]byte, error) {
+	envelope := &tokenEnvelope{}
+	if err := json.Unmarshal(record, envelope); err != nil || envelope.KeyID == "" {
+		return record, nil
+	}
+
+	if c == nil {
+		return nil, errors.New("The token is encrypted but no token keys are configured")
+	}
+
+	master, ok := c.keys[envelope.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("The token is encrypted with the unknown key %s", envelope.KeyID)
+	}
+
+	dataKey, err := open(master, envelope.WrappedKey, []byte(envelope.KeyID+"\x00"+userID))
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return open(data, envelope.Data, []byte(userID))
+}
+
+// Current reports whether a record is sealed with the current master key
+func (c *tokenCipher) Current(record []byte) bool {
+	envelope := &tokenEnvelope{}
+	if err := json.Unmarshal(record, envelope); err != nil {
+		return false
+	}
+	if c == nil {
+		return envelope.KeyID == ""
+	}
+	return envelope.KeyID == c.current
+}
+
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("The token record is corrupt")
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], additionalData)
+	if err != nil {
+		return nil, errors.New("The token record could not be decrypted")
+	}
+	return plaintext, nil
+}